@@ -1,16 +1,61 @@
 package i18n
 
 var EN = Messages{
-	"syntax_error":             "Syntax error",
-	"unknown_person":           "Unknown person - %s",
-	"unknown_person_in_family": "There is no one named %[2]s in the %[1]s family",
-	"unknown_family":           "Unknown family - %s",
-	"create_family_after":      "Create %s family after %s",
-	"create_family_at_end":     "Create %s family at the end of file",
-	"create_family_file":       "Create new file with %s family",
-	"change_name_from_source":  "Change to %s child of %s",
-	"duplicate_count_of_name":  "An unobvious name. There are %d persons with the name %s. Use uniq name alias of one of them",
-	"child_of_source":          "child of %s",
-	"child_without_relations":  "%s %s has no relationship",
-	"create_child_relation":    "Create family relationship",
+	"syntax_error":                 "Syntax error",
+	"unknown_person":               "Unknown person - %s",
+	"unknown_person_in_family":     "There is no one named %[2]s in the %[1]s family",
+	"unknown_family":               "Unknown family - %s",
+	"create_family_after":          "Create %s family after %s",
+	"create_family_at_end":         "Create %s family at the end of file",
+	"create_family_file":           "Create new file with %s family",
+	"change_name_from_source":      "Change to %s child of %s",
+	"duplicate_count_of_name":      "An unobvious name. There are %d persons with the name %s. Use uniq name alias of one of them",
+	"child_of_source":              "child of %s",
+	"child_without_relations":      "%s %s has no relationship",
+	"create_child_relation":        "Create family relationship",
+	"family_declaration":           "Family %s",
+	"extract_family_file":          "Move %s family to a separate file",
+	"merge_family_into":            "Merge %s family into %s from %s:%d",
+	"move_person_to_family":        "Move %s to %s family",
+	"move_person_with_descendants": "Move %s with descendants to %s family",
+	"renumber_children":            "Renumber children",
+	"sort_children_by_name":        "Sort children alphabetically",
+	"sort_children_by_birth":       "Sort children by birth date",
+	"lens_members":                 "%d members",
+	"lens_members_one":             "%d member",
+	"lens_family_refs":             "%d files reference this family",
+	"lens_family_refs_one":         "%d file references this family",
+	"lens_descendants":             "%d descendants",
+	"lens_descendants_one":         "%d descendant",
+	"lens_refs":                    "%d references",
+	"lens_refs_one":                "%d reference",
+	"lens_info":                    "biography",
+	"lens_outdated":                "outdated",
+	"create_biography":             "Create biography of %s",
+	"kinship_same":                 "%s is the same person",
+	"kinship_partner":              "%s is a partner of %s",
+	"kinship_none":                 "%s and %s have no common ancestors",
+	"kinship_child":                "%s is a child of %s",
+	"kinship_grandchild":           "%s is a grandchild of %s",
+	"kinship_descendant":           "%s is a descendant of %s in %d generation",
+	"kinship_parent":               "%s is a parent of %s",
+	"kinship_grandparent":          "%s is a grandparent of %s",
+	"kinship_ancestor":             "%s is an ancestor of %s in %d generation",
+	"kinship_sibling":              "%s is a sibling of %s",
+	"kinship_nephew":               "%s is a nephew or niece of %s",
+	"kinship_uncle":                "%s is an uncle or aunt of %s",
+	"kinship_cousin":               "%s is a cousin of %s of %d degree",
+	"kinship_common_ancestor":      "%s and %s have common ancestor %s %s in %d and %d generations",
+	"orphan_file_family":           "No family %s for this file",
+	"orphan_file_member":           "No member %s in family %s",
+	"orphan_file_similar":          "Similar name %s",
+	"orphan_file_rename":           "Rename to %s",
+	"orphan_file_alias":            "Add %s as alias of %s",
+	"similar_person":               "%s %s is probably the same person as %s %s",
+	"merge_into_reference":         "Replace with reference to %s %s",
+	"snippet_child":                "%d. Next child",
+	"snippet_relation":             "New relation",
+	"snippet_family":               "New family",
+	"completion_partners":          "partners: %s",
+	"completion_children":          "%d children",
 }
@@ -1,16 +1,61 @@
 package i18n
 
 var RU = Messages{
-	"syntax_error":             "Синтаксическая ошибка",
-	"unknown_person":           "Неизвестное имя - %s",
-	"unknown_person_in_family": "В семъе %s нет человека с именем %s",
-	"unknown_family":           "Неизвестная фамилия - %s",
-	"create_family_after":      "Создать семью %s после текущей %s",
-	"create_family_at_end":     "Создать семью %s в конце этого файла",
-	"create_family_file":       "Создать семью %s на новом файле",
-	"change_name_from_source":  "Заменить на %s - ребёнка %s",
-	"duplicate_count_of_name":  "Неоднозначное имя. В этой семье %d имени %s. Используйте уникальный вариант имени одного из них",
-	"child_of_source":          "ребёнок супругов %s",
-	"child_without_relations":  "%s %s не имеет отношений",
-	"create_child_relation":    "Создать семейные отношения",
+	"syntax_error":                 "Синтаксическая ошибка",
+	"unknown_person":               "Неизвестное имя - %s",
+	"unknown_person_in_family":     "В семъе %s нет человека с именем %s",
+	"unknown_family":               "Неизвестная фамилия - %s",
+	"create_family_after":          "Создать семью %s после текущей %s",
+	"create_family_at_end":         "Создать семью %s в конце этого файла",
+	"create_family_file":           "Создать семью %s на новом файле",
+	"change_name_from_source":      "Заменить на %s - ребёнка %s",
+	"duplicate_count_of_name":      "Неоднозначное имя. В этой семье %d имени %s. Используйте уникальный вариант имени одного из них",
+	"child_of_source":              "ребёнок супругов %s",
+	"child_without_relations":      "%s %s не имеет отношений",
+	"create_child_relation":        "Создать семейные отношения",
+	"family_declaration":           "Семья %s",
+	"extract_family_file":          "Перенести семью %s в отдельный файл",
+	"merge_family_into":            "Объединить семью %s с %s из %s:%d",
+	"move_person_to_family":        "Переместить %s в семью %s",
+	"move_person_with_descendants": "Переместить %s с потомками в семью %s",
+	"renumber_children":            "Перенумеровать детей",
+	"sort_children_by_name":        "Сортировать детей по алфавиту",
+	"sort_children_by_birth":       "Сортировать детей по дате рождения",
+	"lens_members":                 "членов: %d",
+	"lens_members_one":             "членов: %d",
+	"lens_family_refs":             "файлов со ссылками: %d",
+	"lens_family_refs_one":         "файлов со ссылками: %d",
+	"lens_descendants":             "потомков: %d",
+	"lens_descendants_one":         "потомков: %d",
+	"lens_refs":                    "ссылок: %d",
+	"lens_refs_one":                "ссылок: %d",
+	"lens_info":                    "биография",
+	"lens_outdated":                "устарело",
+	"create_biography":             "Создать биографию %s",
+	"kinship_same":                 "%s — это тот же человек",
+	"kinship_partner":              "%s — партнёр %s",
+	"kinship_none":                 "У %s и %s нет общих предков",
+	"kinship_child":                "%s — ребёнок %s",
+	"kinship_grandchild":           "%s — внук или внучка %s",
+	"kinship_descendant":           "%s — потомок %s в %d поколении",
+	"kinship_parent":               "%s — отец или мать %s",
+	"kinship_grandparent":          "%s — дед или бабушка %s",
+	"kinship_ancestor":             "%s — предок %s в %d поколении",
+	"kinship_sibling":              "%s — брат или сестра %s",
+	"kinship_nephew":               "%s — племянник или племянница %s",
+	"kinship_uncle":                "%s — дядя или тётя %s",
+	"kinship_cousin":               "%s — кузен или кузина %s %d степени",
+	"kinship_common_ancestor":      "У %s и %s общий предок %s %s в %d и %d поколениях",
+	"orphan_file_family":           "Нет семьи %s для этого файла",
+	"orphan_file_member":           "Нет %s в семье %s",
+	"orphan_file_similar":          "Похожее имя %s",
+	"orphan_file_rename":           "Переименовать в %s",
+	"orphan_file_alias":            "Добавить %s как псевдоним %s",
+	"similar_person":               "%s %s вероятно тот же человек, что и %s %s",
+	"merge_into_reference":         "Заменить на ссылку на %s %s",
+	"snippet_child":                "%d. Следующий ребёнок",
+	"snippet_relation":             "Новое отношение",
+	"snippet_family":               "Новая семья",
+	"completion_partners":          "партнёры: %s",
+	"completion_children":          "детей: %d",
 }
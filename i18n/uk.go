@@ -1,16 +1,61 @@
 package i18n
 
 var UK = Messages{
-	"syntax_error":             "Синтаксична помилка",
-	"unknown_person":           "Невідоме імʼя - %s",
-	"unknown_person_in_family": "В сімʼї %s немає людини з іменем %s",
-	"unknown_family":           "Невідоме прізвище - %s",
-	"create_family_after":      "Створити сімʼю %s після поточної %s",
-	"create_family_at_end":     "Створити сімʼю %s в кінці цього файлу",
-	"create_family_file":       "Створити сімʼю %s у новому файлі",
-	"change_name_from_source":  "Замінити на %s - дитину %s",
-	"duplicate_count_of_name":  "Неоднозначне імʼя. У цій сімʼї %d імені %s. Використайте унікальний варіант імені одного з них",
-	"child_of_source":          "дитина подружжя %s",
-	"child_without_relations":  "%s %s не має відносин",
-	"create_child_relation":    "Створити сімейні відносини",
+	"syntax_error":                 "Синтаксична помилка",
+	"unknown_person":               "Невідоме імʼя - %s",
+	"unknown_person_in_family":     "В сімʼї %s немає людини з іменем %s",
+	"unknown_family":               "Невідоме прізвище - %s",
+	"create_family_after":          "Створити сімʼю %s після поточної %s",
+	"create_family_at_end":         "Створити сімʼю %s в кінці цього файлу",
+	"create_family_file":           "Створити сімʼю %s у новому файлі",
+	"change_name_from_source":      "Замінити на %s - дитину %s",
+	"duplicate_count_of_name":      "Неоднозначне імʼя. У цій сімʼї %d імені %s. Використайте унікальний варіант імені одного з них",
+	"child_of_source":              "дитина подружжя %s",
+	"child_without_relations":      "%s %s не має відносин",
+	"create_child_relation":        "Створити сімейні відносини",
+	"family_declaration":           "Сімʼя %s",
+	"extract_family_file":          "Перенести сімʼю %s в окремий файл",
+	"merge_family_into":            "Обʼєднати сімʼю %s з %s з %s:%d",
+	"move_person_to_family":        "Перемістити %s до сімʼї %s",
+	"move_person_with_descendants": "Перемістити %s з нащадками до сімʼї %s",
+	"renumber_children":            "Перенумерувати дітей",
+	"sort_children_by_name":        "Сортувати дітей за абеткою",
+	"sort_children_by_birth":       "Сортувати дітей за датою народження",
+	"lens_members":                 "членів: %d",
+	"lens_members_one":             "членів: %d",
+	"lens_family_refs":             "файлів з посиланнями: %d",
+	"lens_family_refs_one":         "файлів з посиланнями: %d",
+	"lens_descendants":             "нащадків: %d",
+	"lens_descendants_one":         "нащадків: %d",
+	"lens_refs":                    "посилань: %d",
+	"lens_refs_one":                "посилань: %d",
+	"lens_info":                    "біографія",
+	"lens_outdated":                "застаріло",
+	"create_biography":             "Створити біографію %s",
+	"kinship_same":                 "%s — це та сама особа",
+	"kinship_partner":              "%s — партнер %s",
+	"kinship_none":                 "%s та %s не мають спільних предків",
+	"kinship_child":                "%s — дитина %s",
+	"kinship_grandchild":           "%s — онук або онука %s",
+	"kinship_descendant":           "%s — нащадок %s у %d поколінні",
+	"kinship_parent":               "%s — батько або мати %s",
+	"kinship_grandparent":          "%s — дід або баба %s",
+	"kinship_ancestor":             "%s — предок %s у %d поколінні",
+	"kinship_sibling":              "%s — брат або сестра %s",
+	"kinship_nephew":               "%s — племінник або племінниця %s",
+	"kinship_uncle":                "%s — дядько або тітка %s",
+	"kinship_cousin":               "%s — кузен або кузина %s %d ступеня",
+	"kinship_common_ancestor":      "%s та %s мають спільного предка %s %s у %d та %d поколіннях",
+	"orphan_file_family":           "Немає сімʼї %s для цього файлу",
+	"orphan_file_member":           "Немає %s у сімʼї %s",
+	"orphan_file_similar":          "Схоже імʼя %s",
+	"orphan_file_rename":           "Перейменувати на %s",
+	"orphan_file_alias":            "Додати %s як псевдонім %s",
+	"similar_person":               "%s %s ймовірно та сама особа, що й %s %s",
+	"merge_into_reference":         "Замінити на посилання на %s %s",
+	"snippet_child":                "%d. Наступна дитина",
+	"snippet_relation":             "Нове відношення",
+	"snippet_family":               "Нова сімʼя",
+	"completion_partners":          "партнери: %s",
+	"completion_children":          "дітей: %d",
 }
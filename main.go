@@ -1,9 +1,47 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	lsp "github.com/redexp/familymarkup-lsp/providers"
 )
 
+const usage = `Usage:
+  familymarkup-lsp                          start language server
+  familymarkup-lsp stats [path...]          print statistics of archive
+  familymarkup-lsp query <query> [path...]  print persons found by query
+  familymarkup-lsp diff <old> <new>         print changes between two folders or two family files`
+
 func main() {
-	lsp.StartServer()
+	var err error
+	args := os.Args[1:]
+	command := ""
+
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch {
+	case command == "stats":
+		err = lsp.PrintStats(args[1:], os.Stdout)
+
+	case command == "query" && len(args) > 1:
+		err = lsp.PrintQuery(args[1], args[2:], os.Stdout)
+
+	case command == "diff" && len(args) > 2:
+		err = lsp.PrintDiff(args[1], args[2], os.Stdout)
+
+	case command == "query" || command == "diff" || command == "help":
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+
+	default:
+		lsp.StartServer()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
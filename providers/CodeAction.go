@@ -16,6 +16,10 @@ type CodeActionData struct {
 	Type uint8  `json:"type"`
 	Mod  uint8  `json:"mod"`
 	Name string `json:"name"`
+	Line int    `json:"line"`
+
+	TargetUri  string `json:"targetUri"`
+	TargetLine int    `json:"targetLine"`
 }
 
 const (
@@ -25,7 +29,9 @@ const (
 )
 
 func CodeAction(_ *Ctx, params *proto.CodeActionParams) (res any, err error) {
-	if len(params.Context.Diagnostics) == 0 {
+	err = root.UpdateDirty()
+
+	if err != nil {
 		return
 	}
 
@@ -149,6 +155,37 @@ func CodeAction(_ *Ctx, params *proto.CodeActionParams) (res any, err error) {
 				})
 			}
 
+		case OrphanFileWarning:
+			add(getOrphanActions(uri, d)...)
+
+		case SimilarPersonHint:
+			family := root.Families[data.Surname]
+
+			if family == nil {
+				continue
+			}
+
+			member := family.GetMember(data.Name)
+
+			if member == nil {
+				continue
+			}
+
+			for _, item := range root.FindSimilarMembers()[member] {
+				add(proto.CodeAction{
+					Title:       L("merge_into_reference", item.Name, item.GetSurname()),
+					Kind:        QuickFix,
+					Diagnostics: []proto.Diagnostic{d},
+					Data: CodeActionData{
+						Uri:        uri,
+						Type:       SimilarPersonHint,
+						Name:       item.Name,
+						TargetUri:  item.Family.Uri,
+						TargetLine: item.Person.Name.Line,
+					},
+				})
+			}
+
 		case ChildWithoutRelationsInfo:
 			add(proto.CodeAction{
 				Title:       L("create_child_relation"),
@@ -162,11 +199,14 @@ func CodeAction(_ *Ctx, params *proto.CodeActionParams) (res any, err error) {
 		}
 	}
 
+	add(getRefactorActions(uri, params)...)
+	add(getSourceActions(uri, params)...)
+
 	return list, nil
 }
 
 func CodeActionResolve(_ *Ctx, params *proto.CodeAction) (res *proto.CodeAction, err error) {
-	if len(params.Diagnostics) == 0 || params.Data == nil {
+	if params.Data == nil {
 		return
 	}
 
@@ -178,6 +218,21 @@ func CodeActionResolve(_ *Ctx, params *proto.CodeAction) (res *proto.CodeAction,
 		return
 	}
 
+	if len(params.Diagnostics) == 0 {
+		return resolveRefactor(&data)
+	}
+
+	if data.Type == OrphanFileWarning {
+		res = params
+		res.Edit, err = resolveOrphanAction(&data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return
+	}
+
 	r := params.Diagnostics[0].Range
 
 	var token *fm.Token
@@ -218,27 +273,12 @@ func CodeActionResolve(_ *Ctx, params *proto.CodeAction) (res *proto.CodeAction,
 			doc.Version++
 			doc.NeedDiagnostic = true
 
-			newUri, err := RenameUri(data.Uri, surname)
+			res.Edit.DocumentChanges, err = createFamilyFile(data.Uri, surname, text)
 
 			if err != nil {
 				return nil, err
 			}
 
-			createFile := proto.CreateFile{
-				Kind: "create",
-				URI:  newUri,
-			}
-
-			pos := Position{
-				Line:      0,
-				Character: 0,
-			}
-
-			res.Edit.DocumentChanges = []any{
-				createFile,
-				createInsertText(newUri, pos, text),
-			}
-
 			return res, nil
 		}
 
@@ -261,6 +301,13 @@ func CodeActionResolve(_ *Ctx, params *proto.CodeAction) (res *proto.CodeAction,
 	case NameDuplicateWarning:
 		res.Edit.DocumentChanges = []any{createEdit(data.Uri, r.Start, r.End, data.Name)}
 
+	case SimilarPersonHint:
+		res.Edit.Changes, err = mergeIntoReference(&data, r)
+
+		if err != nil {
+			return nil, err
+		}
+
 	case ChildWithoutRelationsInfo:
 		doc := GetDoc(data.Uri)
 
@@ -284,6 +331,37 @@ func CodeActionResolve(_ *Ctx, params *proto.CodeAction) (res *proto.CodeAction,
 	return res, nil
 }
 
+// createFamilyFile creates file named by surname near uri, or appends text to the end of existing one
+func createFamilyFile(uri Uri, surname string, text string) (changes []any, err error) {
+	newUri, err := RenameUri(uri, surname)
+
+	if err != nil {
+		return
+	}
+
+	if doc, ok := root.Docs[newUri]; ok {
+		changes = []any{createInsertText(newUri, LocPosToPosition(doc.Root.End), "\n\n"+text)}
+		return
+	}
+
+	createFile := proto.CreateFile{
+		Kind: "create",
+		URI:  newUri,
+	}
+
+	pos := Position{
+		Line:      0,
+		Character: 0,
+	}
+
+	changes = []any{
+		createFile,
+		createInsertText(newUri, pos, text),
+	}
+
+	return
+}
+
 func createEdit(uri Uri, start proto.Position, end proto.Position, text string) proto.TextDocumentEdit {
 	return proto.TextDocumentEdit{
 		TextDocument: proto.OptionalVersionedTextDocumentIdentifier{
@@ -304,3 +382,79 @@ func createEdit(uri Uri, start proto.Position, end proto.Position, text string)
 func createInsertText(uri Uri, pos proto.Position, text string) proto.TextDocumentEdit {
 	return createEdit(uri, pos, pos, text)
 }
+
+// mergeIntoReference replaces definition of the person with reference "Name Surname" to the similar person
+// and rewrites references of the person to "Name Surname" of the similar person
+func mergeIntoReference(data *CodeActionData, r Range) (changes map[Uri][]proto.TextEdit, err error) {
+	doc := GetDoc(data.Uri)
+	target := GetDoc(data.TargetUri)
+
+	if doc == nil || target == nil {
+		return nil, fmt.Errorf("document not found")
+	}
+
+	token := doc.GetTokenByPosition(r.Start)
+
+	if token == nil {
+		return nil, fmt.Errorf("person not found")
+	}
+
+	member := root.GetMemberByToken(doc.Uri, token)
+	p := findPersonByName(target, data.TargetLine, data.Name)
+
+	if member == nil || p == nil {
+		return nil, fmt.Errorf("person not found")
+	}
+
+	other := getPersonMember(target, p)
+
+	if other == nil || other == member {
+		return nil, fmt.Errorf("person not found")
+	}
+
+	changes = make(map[Uri][]proto.TextEdit)
+	surname := other.GetSurname()
+
+	// member will not exist anymore, so all references including names resolved by context of the family
+	// should point to the other person by name and surname
+	for ref, uri := range member.GetRefsIter() {
+		p := ref.Person
+
+		if p == nil || p.Name == nil {
+			continue
+		}
+
+		// person came to another family, names in that family are resolved by context
+		if ref.Type == RefTypeOrigin && ref.Member != member {
+			for r, u := range ref.Member.GetRefsIter() {
+				rp := r.Person
+
+				if rp == nil || rp == p || rp.Name == nil || rp.Surname != nil || rp.Name.Text == other.Name {
+					continue
+				}
+
+				changes[u] = append(changes[u], proto.TextEdit{
+					Range:   TokenToRange(rp.Name),
+					NewText: other.Name,
+				})
+			}
+		}
+
+		if p != member.Person && p.Surname != nil && p.Name.Text == other.Name && p.Surname.Text == surname {
+			continue
+		}
+
+		r := TokenToRange(p.Name)
+
+		if p.Surname != nil {
+			r.End = TokenToRange(p.Surname).End
+		}
+
+		changes[uri] = append(changes[uri], proto.TextEdit{
+			Range:   r,
+			NewText: other.Name + " " + surname,
+		})
+	}
+
+	return
+}
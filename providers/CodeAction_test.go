@@ -0,0 +1,36 @@
+package providers
+
+import (
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+func TestMergeIntoReference(t *testing.T) {
+	files := map[string]string{
+		"Shevchenko.fml": "Shevchenko\n\nYurii + Olena =\n1. Taras\n",
+		"Ukr.fml":        "Шевченко\n\nЮрій + Олена =\n1. Тарас\n\nТарас + Ольга =\n1. Іван\n",
+		"Refs.fml":       "Refs\n\nТарас Шевченко + Anna =\n1. Ivan\n\nIvan + Тарас =\n1. Olha\n",
+	}
+
+	folder := testFolder(t, files)
+	target := folder + "/Shevchenko.fml"
+
+	changes, err := mergeIntoReference(&CodeActionData{
+		Uri:        folder + "/Ukr.fml",
+		Type:       SimilarPersonHint,
+		Name:       "Taras",
+		TargetUri:  target,
+		TargetLine: 3,
+	}, PositionToRange(Position{Line: 3, Character: 4}))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkFiles(t, "merge into reference", folder, files, changes, map[string]string{
+		"Ukr.fml":  "Шевченко\n\nЮрій + Олена =\n1. Taras Shevchenko\n\nTaras Shevchenko + Ольга =\n1. Іван\n",
+		"Refs.fml": "Refs\n\nTaras Shevchenko + Anna =\n1. Ivan\n\nIvan + Taras =\n1. Olha\n",
+	})
+}
@@ -0,0 +1,234 @@
+package providers
+
+import (
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	fm "github.com/redexp/familymarkup-parser"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+type CodeLensData struct {
+	Uri  string `json:"uri"`
+	Type uint8  `json:"type"`
+	Line int    `json:"line"`
+	Char int    `json:"char"`
+}
+
+const (
+	FamilyLens = iota
+	PersonLens
+	PersonInfoLens
+)
+
+// client command of lenses with counts
+const ClientShowReferencesCommand = "editor.action.showReferences"
+
+func CodeLens(_ *Ctx, params *proto.CodeLensParams) (res []proto.CodeLens, err error) {
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	uri := NormalizeUri(params.TextDocument.URI)
+	doc := GetDoc(uri)
+	res = make([]proto.CodeLens, 0)
+
+	if doc == nil {
+		return
+	}
+
+	add := func(token *fm.Token, t uint8) {
+		res = append(res, proto.CodeLens{
+			Range: TokenToRange(token),
+			Data: CodeLensData{
+				Uri:  uri,
+				Type: t,
+				Line: token.Line,
+				Char: token.Char,
+			},
+		})
+	}
+
+	for _, f := range doc.Root.Families {
+		add(f.Name, FamilyLens)
+
+		for _, rel := range f.Relations {
+			for _, p := range rel.Sources.Persons {
+				if p.Name == nil {
+					continue
+				}
+
+				mem := root.GetMemberByToken(uri, p.Name)
+
+				if mem == nil {
+					continue
+				}
+
+				add(p.Name, PersonLens)
+
+				if mem.GetOrigin().InfoUri != "" {
+					add(p.Name, PersonInfoLens)
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// CodeLensResolve sets lens with counts, which shows their locations by client command, or lens of biography
+func CodeLensResolve(_ *Ctx, params *proto.CodeLens) (res *proto.CodeLens, err error) {
+	res = params
+
+	var data CodeLensData
+
+	err = mapstructure.Decode(params.Data, &data)
+
+	if err != nil {
+		return
+	}
+
+	position := Position{
+		Line:      uint32(data.Line),
+		Character: uint32(data.Char),
+	}
+
+	ref := root.GetRefByPosition(data.Uri, position)
+
+	if ref == nil {
+		res.Command = &proto.Command{Title: L("lens_outdated")}
+		return
+	}
+
+	var title []string
+	var locations []proto.Location
+
+	switch data.Type {
+	case FamilyLens:
+		if ref.Family == nil {
+			return
+		}
+
+		title, locations = familyLens(ref.Family)
+
+	case PersonLens:
+		if ref.Member == nil {
+			return
+		}
+
+		title, locations = personLens(ref.Member)
+
+	case PersonInfoLens:
+		if ref.Member == nil || ref.Member.GetOrigin().InfoUri == "" {
+			return
+		}
+
+		res.Command = &proto.Command{
+			Title:   L("lens_info"),
+			Command: OpenBiographyCommand,
+			Arguments: []any{CommandLocation{
+				Uri:      data.Uri,
+				Position: position,
+			}},
+		}
+
+		return
+	}
+
+	if locations == nil {
+		locations = make([]proto.Location, 0)
+	}
+
+	res.Command = &proto.Command{
+		Title:     strings.Join(title, " · "),
+		Command:   ClientShowReferencesCommand,
+		Arguments: []any{data.Uri, position, locations},
+	}
+
+	return
+}
+
+// familyLens returns title parts with count of members and count of files which reference the family
+// and locations of members and references
+func familyLens(f *Family) (title []string, locations []proto.Location) {
+	members := 0
+
+	for mem := range f.MembersIter() {
+		members++
+
+		locations = append(locations, proto.Location{
+			URI:   f.Uri,
+			Range: TokenToRange(mem.Person.Name),
+		})
+	}
+
+	files := make(UriSet)
+
+	for ref, uri := range f.GetRefsIter() {
+		if ref.Token == f.Node.Name {
+			continue
+		}
+
+		if uri != f.Uri {
+			files.Set(uri)
+		}
+
+		locations = append(locations, proto.Location{
+			URI:   uri,
+			Range: TokenToRange(ref.Token),
+		})
+	}
+
+	title = []string{lensCount("lens_members", members), lensCount("lens_family_refs", len(files))}
+
+	return
+}
+
+// personLens returns title parts with count of descendants and count of references
+// and locations of descendants and references
+func personLens(member *Member) (title []string, locations []proto.Location) {
+	origin := member.GetOrigin()
+	descendants := 0
+
+	for mem := range root.DescendantsIter(origin) {
+		descendants++
+
+		locations = append(locations, proto.Location{
+			URI:   mem.Family.Uri,
+			Range: TokenToRange(mem.Person.Name),
+		})
+	}
+
+	refs := 0
+
+	for r, uri := range origin.GetAllRefsIter() {
+		if r.Person == origin.Person {
+			continue
+		}
+
+		refs++
+
+		locations = append(locations, proto.Location{
+			URI:   uri,
+			Range: TokenToRange(r.Token),
+		})
+	}
+
+	title = []string{lensCount("lens_descendants", descendants), lensCount("lens_refs", refs)}
+
+	return
+}
+
+// lensCount returns message of count with singular form by key with "_one" suffix
+func lensCount(key string, count int) string {
+	if count == 1 {
+		key += "_one"
+	}
+
+	return L(key, count)
+}
@@ -0,0 +1,62 @@
+package providers
+
+import (
+	"testing"
+
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestCodeLens(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml":      "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny =\n1. Albus\n2. Lily\n",
+		"Potter/James.md": "# James\n",
+	})
+
+	uri := folder + "/Potter.fml"
+
+	list, err := CodeLens(nil, &proto.CodeLensParams{
+		TextDocument: proto.TextDocumentIdentifier{URI: uri},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := [][2]string{
+		{"6 members · 0 files reference this family", ClientShowReferencesCommand},
+		{"3 descendants · 0 references", ClientShowReferencesCommand},
+		{"biography", OpenBiographyCommand},
+		{"3 descendants · 0 references", ClientShowReferencesCommand},
+		{"2 descendants · 1 reference", ClientShowReferencesCommand},
+		{"2 descendants · 0 references", ClientShowReferencesCommand},
+	}
+
+	if len(list) != len(expect) {
+		t.Fatalf("lenses: %d", len(list))
+	}
+
+	for i, lens := range list {
+		res, err := CodeLensResolve(nil, &lens)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if res.Command == nil || res.Command.Title != expect[i][0] || res.Command.Command != expect[i][1] {
+			t.Errorf("lens %d: %+v", i, res.Command)
+			continue
+		}
+
+		if i == 0 {
+			if locations, ok := res.Command.Arguments[2].([]proto.Location); !ok || len(locations) != 6 {
+				t.Errorf("family lens locations: %v", res.Command.Arguments)
+			}
+		}
+	}
+
+	locations, err := showReferences([]any{CommandLocation{Uri: uri}})
+
+	if err != nil || len(locations) != 6 {
+		t.Errorf("family locations: %v %v", locations, err)
+	}
+}
@@ -0,0 +1,444 @@
+package providers
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	urlParser "net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+const (
+	ShowKinshipCommand     = "familymarkup.showKinship"
+	ExportGedcomCommand    = "familymarkup.exportGedcom"
+	OpenBiographyCommand   = "familymarkup.openBiography"
+	CreateBiographyCommand = "familymarkup.createBiography"
+	SemanticDiffCommand    = "familymarkup.semanticDiff"
+	ShowReferencesCommand  = "familymarkup.showReferences"
+)
+
+var Commands = []string{
+	ShowKinshipCommand,
+	ExportGedcomCommand,
+	OpenBiographyCommand,
+	CreateBiographyCommand,
+	SemanticDiffCommand,
+	ShowReferencesCommand,
+}
+
+// CommandLocation is an argument of commands which points to a person
+type CommandLocation struct {
+	Uri      Uri      `json:"uri"`
+	Position Position `json:"position"`
+}
+
+func ExecuteCommand(ctx *Ctx, params *proto.ExecuteCommandParams) (res any, err error) {
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	switch params.Command {
+	case ShowKinshipCommand:
+		return showKinship(ctx, params.Arguments)
+
+	case ExportGedcomCommand:
+		return exportGedcom(params.Arguments)
+
+	case OpenBiographyCommand:
+		return openBiography(ctx, params.Arguments)
+
+	case CreateBiographyCommand:
+		return createBiography(ctx, params.Arguments)
+
+	case SemanticDiffCommand:
+		return semanticDiff(params.Arguments)
+
+	case ShowReferencesCommand:
+		return showReferences(params.Arguments)
+	}
+
+	return nil, fmt.Errorf("unknown command: %s", params.Command)
+}
+
+// semanticDiff returns changes of persons and relations between two folders or two family files,
+// arguments are uris of old and new versions
+func semanticDiff(args []any) (res []DiffChange, err error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("arguments should be uris of old and new versions")
+	}
+
+	paths := make([]string, 2)
+
+	for i := range paths {
+		uri, ok := args[i].(string)
+
+		if !ok {
+			return nil, fmt.Errorf("argument should be an uri")
+		}
+
+		paths[i], err = UriToPath(NormalizeUri(uri))
+
+		if err != nil {
+			return
+		}
+	}
+
+	res, err = diffPaths(paths[0], paths[1])
+
+	if res == nil && err == nil {
+		res = make([]DiffChange, 0)
+	}
+
+	return
+}
+
+func diffPaths(before string, after string) (res []DiffChange, err error) {
+	a, err := LoadSnapshot(before)
+
+	if err != nil {
+		return
+	}
+
+	b, err := LoadSnapshot(after)
+
+	if err != nil {
+		return
+	}
+
+	return Diff(a, b), nil
+}
+
+// PrintDiff writes changes between two folders or two family files line by line, used by "diff" command of CLI
+func PrintDiff(before string, after string, w io.Writer) (err error) {
+	list, err := diffPaths(before, after)
+
+	if err != nil {
+		return
+	}
+
+	for _, change := range list {
+		_, err = fmt.Fprintln(w, change.String())
+
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// showKinship shows kinship of two persons, arguments are two CommandLocation
+func showKinship(ctx *Ctx, args []any) (res string, err error) {
+	a, err := getCommandMember(args, 0)
+
+	if err != nil {
+		return
+	}
+
+	b, err := getCommandMember(args, 1)
+
+	if err != nil {
+		return
+	}
+
+	res = getKinshipText(a.GetOrigin(), b.GetOrigin())
+
+	if ctx == nil {
+		return
+	}
+
+	ctx.Notify(proto.ServerWindowShowMessage, proto.ShowMessageParams{
+		Type:    proto.MessageTypeInfo,
+		Message: res,
+	})
+
+	return
+}
+
+// showReferences returns locations of counts of code lens on family or person from CommandLocation
+func showReferences(args []any) (res []proto.Location, err error) {
+	ref, err := getCommandRef(args, 0)
+
+	if err != nil {
+		return
+	}
+
+	if ref.Member == nil {
+		_, res = familyLens(ref.Family)
+
+		return
+	}
+
+	_, res = personLens(ref.Member)
+
+	return
+}
+
+// exportGedcom returns GEDCOM of the workspace or writes it to the file from the first argument
+func exportGedcom(args []any) (res string, err error) {
+	res = ExportGedcom()
+
+	if len(args) == 0 {
+		return
+	}
+
+	uri, ok := args[0].(string)
+
+	if !ok {
+		return "", fmt.Errorf("argument should be an uri of file")
+	}
+
+	path, err := UriToPath(uri)
+
+	if err != nil {
+		return
+	}
+
+	err = os.WriteFile(path, []byte(res), 0644)
+
+	if err != nil {
+		return
+	}
+
+	return uri, nil
+}
+
+// openBiography opens Markdown file of the person from CommandLocation
+func openBiography(ctx *Ctx, args []any) (res Uri, err error) {
+	mem, err := getCommandMember(args, 0)
+
+	if err != nil {
+		return
+	}
+
+	res = mem.GetOrigin().InfoUri
+
+	if res == "" {
+		return "", fmt.Errorf("biography of %s not found", mem.Name)
+	}
+
+	go showDocument(ctx, res)
+
+	return
+}
+
+// createBiography creates Markdown file of the person from CommandLocation
+func createBiography(ctx *Ctx, args []any) (res Uri, err error) {
+	mem, err := getCommandMember(args, 0)
+
+	if err != nil {
+		return
+	}
+
+	mem = mem.GetOrigin()
+
+	if mem.InfoUri != "" {
+		go showDocument(ctx, mem.InfoUri)
+
+		return mem.InfoUri, nil
+	}
+
+	res, err = getBiographyUri(mem)
+
+	if err != nil {
+		return
+	}
+
+	text := getBiographyTemplate(mem)
+
+	go func() {
+		var result proto.ApplyWorkspaceEditResponse
+
+		ctx.Call(proto.ServerWorkspaceApplyEdit, proto.ApplyWorkspaceEditParams{
+			Label: new(L("create_biography", mem.Name)),
+			Edit: proto.WorkspaceEdit{
+				DocumentChanges: []any{
+					proto.CreateFile{
+						Kind: "create",
+						URI:  res,
+					},
+					createInsertText(res, Position{}, text),
+				},
+			},
+		}, &result)
+
+		if !result.Applied {
+			return
+		}
+
+		root.UpdateLock.Lock()
+		root.AddUnknownFile(NormalizeUri(res))
+		root.UpdateUnknownFiles()
+		root.UpdateLock.Unlock()
+
+		showDocument(ctx, res)
+	}()
+
+	return
+}
+
+// getBiographyUri returns uri of Markdown file of member according to info settings of workspace configuration,
+// names are escaped so they could contain spaces and any letters
+func getBiographyUri(mem *Member) (Uri, error) {
+	config := root.GetConfig(mem.Family.Uri)
+	base, err := urlParser.Parse(root.InfoFolder(mem.Family.Uri))
+
+	if err != nil {
+		return "", err
+	}
+
+	ext := "md"
+
+	if len(config.Extensions.Markdown) > 0 {
+		ext = config.Extensions.Markdown[0]
+	}
+
+	if config.Info.Layout == InfoLayoutIndex {
+		base.Path = path.Join(base.Path, mem.Family.Name, mem.Name, "index."+ext)
+	} else {
+		base.Path = path.Join(base.Path, mem.Family.Name, mem.Name+"."+ext)
+	}
+
+	return base.String(), nil
+}
+
+// getBiographyTemplate returns Markdown with front matter of person's parents and partners
+func getBiographyTemplate(mem *Member) string {
+	names := func(list iter.Seq[*Member]) string {
+		var res []string
+
+		for m := range list {
+			res = append(res, m.Name+" "+m.GetSurname())
+		}
+
+		return strings.Join(res, ", ")
+	}
+
+	var s strings.Builder
+
+	s.WriteString("---\n")
+	s.WriteString(fmt.Sprintf("name: %s\n", mem.Name))
+	s.WriteString(fmt.Sprintf("surname: %s\n", mem.GetSurname()))
+
+	if len(mem.Aliases) > 0 {
+		s.WriteString(fmt.Sprintf("aliases: %s\n", strings.Join(mem.Aliases, ", ")))
+	}
+
+	s.WriteString("born: \n")
+
+	if parents := names(root.ParentsIter(mem)); parents != "" {
+		s.WriteString(fmt.Sprintf("parents: %s\n", parents))
+	}
+
+	if partners := names(root.PartnersIter(mem)); partners != "" {
+		s.WriteString(fmt.Sprintf("partners: %s\n", partners))
+	}
+
+	s.WriteString("---\n\n")
+	s.WriteString(fmt.Sprintf("# %s %s\n", mem.Name, mem.GetSurname()))
+
+	return s.String()
+}
+
+func showDocument(ctx *Ctx, uri Uri) {
+	var result proto.ShowDocumentResult
+
+	ctx.Call(proto.ServerWindowShowDocument, proto.ShowDocumentParams{
+		URI:       uri,
+		TakeFocus: new(true),
+	}, &result)
+}
+
+func getCommandMember(args []any, index int) (mem *Member, err error) {
+	ref, err := getCommandRef(args, index)
+
+	if err != nil {
+		return
+	}
+
+	if ref.Member == nil {
+		return nil, fmt.Errorf("person not found")
+	}
+
+	return ref.Member, nil
+}
+
+// getCommandRef returns reference of family or person from CommandLocation argument
+func getCommandRef(args []any, index int) (ref *Ref, err error) {
+	if index >= len(args) {
+		return nil, fmt.Errorf("argument %d is required", index+1)
+	}
+
+	var loc CommandLocation
+
+	err = mapstructure.Decode(args[index], &loc)
+
+	if err != nil {
+		return
+	}
+
+	ref = root.GetRefByPosition(NormalizeUri(loc.Uri), loc.Position)
+
+	if ref == nil || (ref.Member == nil && ref.Family == nil) {
+		return nil, fmt.Errorf("family or person not found")
+	}
+
+	return
+}
+
+func getKinshipText(a *Member, b *Member) string {
+	if a == b {
+		return L("kinship_same", a.Name)
+	}
+
+	for partner := range root.PartnersIter(a) {
+		if partner == b {
+			return L("kinship_partner", b.Name, a.Name)
+		}
+	}
+
+	ancestor, da, db := root.Kinship(a, b)
+
+	if ancestor == nil {
+		return L("kinship_none", a.Name, b.Name)
+	}
+
+	var key string
+
+	switch {
+	case da == 0 && db == 1:
+		key = "kinship_child"
+	case da == 0 && db == 2:
+		key = "kinship_grandchild"
+	case da == 0:
+		return L("kinship_descendant", b.Name, a.Name, db)
+	case da == 1 && db == 0:
+		key = "kinship_parent"
+	case da == 2 && db == 0:
+		key = "kinship_grandparent"
+	case db == 0:
+		return L("kinship_ancestor", b.Name, a.Name, da)
+	case da == 1 && db == 1:
+		key = "kinship_sibling"
+	case da == 1 && db == 2:
+		key = "kinship_nephew"
+	case da == 2 && db == 1:
+		key = "kinship_uncle"
+	case da == db:
+		return L("kinship_cousin", b.Name, a.Name, da-1)
+	default:
+		return L("kinship_common_ancestor", a.Name, b.Name, ancestor.Name, ancestor.Family.Name, da, db)
+	}
+
+	return L(key, b.Name, a.Name)
+}
@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestShowKinshipWithoutClient(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n",
+	})
+
+	uri := folder + "/Potter.fml"
+
+	res, err := ExecuteCommand(nil, &proto.ExecuteCommandParams{
+		Command: ShowKinshipCommand,
+		Arguments: []any{
+			CommandLocation{Uri: uri, Position: Position{Line: 3, Character: 3}},
+			CommandLocation{Uri: uri, Position: Position{Line: 4, Character: 3}},
+		},
+	})
+
+	if err != nil || res != "Rose is a sibling of Harry" {
+		t.Errorf("%v %v", res, err)
+	}
+}
+
+func TestGetBiographyUri(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Шевченко.fml": "Шевченко\n\nГригорій + Катерина =\n1. Тарас\n",
+		"Potter.fml":   "Potter\n\nJames + Lily =\n1. Harry\n",
+	})
+
+	list := map[string]string{
+		"Шевченко/Тарас": "/%D0%A8%D0%B5%D0%B2%D1%87%D0%B5%D0%BD%D0%BA%D0%BE/%D0%A2%D0%B0%D1%80%D0%B0%D1%81.md",
+		"Potter/Lily":    "/Potter/Lily.md",
+	}
+
+	for name, expect := range list {
+		surname, given, _ := strings.Cut(name, "/")
+		_, mem := root.FindMember(surname, given)
+
+		if mem == nil {
+			t.Fatalf("%s not found", name)
+		}
+
+		uri, err := getBiographyUri(mem)
+
+		if err != nil || uri != folder+expect {
+			t.Errorf("%s: %s %v", name, uri, err)
+		}
+
+		if path, _ := UriToPath(uri); path != strings.TrimPrefix(folder, "file://")+"/"+name+".md" {
+			t.Errorf("%s: path %s", name, path)
+		}
+	}
+}
+
+func TestCreateBiographyAction(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml":      "Potter\n\nJames + Lily =\n1. Harry\n",
+		"Weasley.fml":     "Weasley\n\nHarry Potter + Ginny =\n1. Albus\n",
+		"Potter/James.md": "# James\n",
+	})
+
+	count := func(uri Uri, pos Position) (res int) {
+		for _, action := range getRefactorActions(uri, &proto.CodeActionParams{
+			Range: proto.Range{Start: pos, End: pos},
+		}) {
+			if action.Command != nil && action.Command.Command == CreateBiographyCommand {
+				res++
+			}
+		}
+
+		return
+	}
+
+	if n := count(folder+"/Weasley.fml", Position{Line: 2, Character: 1}); n != 1 {
+		t.Errorf("reference in another file: %d", n)
+	}
+
+	if n := count(folder+"/Potter.fml", Position{Line: 2, Character: 1}); n != 0 {
+		t.Errorf("person with biography: %d", n)
+	}
+}
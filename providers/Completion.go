@@ -1,6 +1,13 @@
 package providers
 
 import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
 	. "github.com/redexp/familymarkup-lsp/state"
 	. "github.com/redexp/familymarkup-lsp/types"
 	. "github.com/redexp/familymarkup-lsp/utils"
@@ -8,9 +15,31 @@ import (
 	proto "github.com/tliron/glsp/protocol_3_16"
 )
 
+type CompletionData struct {
+	Type    uint8  `json:"type"`
+	Surname string `json:"surname"`
+	Name    string `json:"name,omitempty"`
+}
+
+const (
+	CompletionFamily = iota
+	CompletionMember
+)
+
 func Completion(_ *Ctx, params *proto.CompletionParams) (res any, err error) {
 	uri := NormalizeUri(params.TextDocument.URI)
 
+	snippets := getSnippets(GetDoc(uri), params.Position)
+
+	defer func() {
+		if err != nil || len(snippets) == 0 {
+			return
+		}
+
+		list, _ := res.([]proto.CompletionItem)
+		res = append(list, snippets...)
+	}()
+
 	t, words, err := GetCompletionType(uri, params.Position)
 
 	if err != nil || t == "" {
@@ -26,39 +55,112 @@ func Completion(_ *Ctx, params *proto.CompletionParams) (res any, err error) {
 
 	kind := new(proto.CompletionItemKindVariable)
 
-	add := func(names ...string) {
+	add := func(data *CompletionData, names ...string) {
 		for _, name := range names {
-			_, exist := hash[name]
+			key := name
 
-			if exist {
+			// members with the same name from different families are different items
+			if data != nil && data.Type == CompletionMember {
+				key += "\n" + data.Surname
+			}
+
+			if hash[name] || hash[key] {
 				continue
 			}
 
-			hash[name] = true
+			hash[key] = true
 
-			list = append(list, proto.CompletionItem{
+			item := proto.CompletionItem{
 				Kind:  kind,
 				Label: name,
-			})
+			}
+
+			if data != nil {
+				item.Data = *data
+			}
+
+			if key != name {
+				item.Detail = new(data.Surname)
+			}
+
+			list = append(list, item)
 		}
 	}
 
 	addFamily := func(family *Family) {
-		add(family.Name)
-		add(family.Aliases...)
+		data := &CompletionData{
+			Type:    CompletionFamily,
+			Surname: family.Name,
+		}
+
+		add(data, family.Name)
+		add(data, family.Aliases...)
 	}
 
 	addMembers := func(family *Family) {
 		for member := range family.MembersIter() {
-			add(member.Name)
-			add(member.Aliases...)
+			data := &CompletionData{
+				Type:    CompletionMember,
+				Surname: family.Name,
+				Name:    member.Name,
+			}
+
+			add(data, member.Name)
+			add(data, member.Aliases...)
 		}
 	}
 
 	if t == "= |" || t == "= label|" {
 		for _, labels := range root.Labels {
 			for _, label := range labels {
-				add(label)
+				add(nil, label)
+			}
+		}
+
+		return list, nil
+	}
+
+	if t == "+ |" || t == "+ name" {
+		doc := GetDoc(uri)
+		f := doc.FindFamilyByRange(PositionToRange(params.Position))
+		partners := getMembersWithPartners()
+
+		for family := range root.FamilyIter() {
+			if f != nil && family.Node == f {
+				addMembers(family)
+				continue
+			}
+
+			for member := range family.MembersIter() {
+				if member.Origin != nil {
+					continue
+				}
+
+				label := member.Name + " " + family.Name
+
+				if hash[label] {
+					continue
+				}
+
+				hash[label] = true
+
+				// people without a partner are more likely to be a new partner
+				sort := "1"
+
+				if partners[member] {
+					sort = "2"
+				}
+
+				list = append(list, proto.CompletionItem{
+					Kind:     kind,
+					Label:    label,
+					SortText: new(sort + label),
+					Data: CompletionData{
+						Type:    CompletionMember,
+						Surname: family.Name,
+						Name:    member.Name,
+					},
+				})
 			}
 		}
 
@@ -118,7 +220,7 @@ func Completion(_ *Ctx, params *proto.CompletionParams) (res any, err error) {
 	if t == "surname" {
 		for _, ref := range root.UnknownRefs {
 			if ref.Type == RefTypeSurname && ref.Token != nil {
-				add(ref.Token.Text)
+				add(nil, ref.Token.Text)
 			}
 		}
 	}
@@ -126,7 +228,7 @@ func Completion(_ *Ctx, params *proto.CompletionParams) (res any, err error) {
 	if t == "name" {
 		for _, ref := range root.UnknownRefs {
 			if ref.Person != nil {
-				add(ref.Person.Name.Text)
+				add(nil, ref.Person.Name.Text)
 			}
 		}
 	}
@@ -134,8 +236,205 @@ func Completion(_ *Ctx, params *proto.CompletionParams) (res any, err error) {
 	return list, nil
 }
 
+func CompletionResolve(_ *Ctx, item *proto.CompletionItem) (res *proto.CompletionItem, err error) {
+	res = item
+
+	if item.Data == nil {
+		return
+	}
+
+	var data CompletionData
+
+	err = mapstructure.Decode(item.Data, &data)
+
+	if err != nil {
+		return
+	}
+
+	family, exist := root.Families[data.Surname]
+
+	if !exist {
+		return
+	}
+
+	var text string
+
+	switch data.Type {
+	case CompletionFamily:
+		text = getFamilyDocumentation(family)
+
+	case CompletionMember:
+		mem := family.GetMember(data.Name)
+
+		if mem == nil {
+			return
+		}
+
+		text = getMemberDocumentation(mem)
+	}
+
+	res.Documentation = proto.MarkupContent{
+		Kind:  proto.MarkupKindMarkdown,
+		Value: text,
+	}
+
+	return
+}
+
+func getFamilyDocumentation(family *Family) string {
+	count := 0
+
+	for range family.MembersIter() {
+		count++
+	}
+
+	lines := []string{
+		"**" + formatNames(family.Name, family.Aliases) + "**",
+		"",
+		"- " + L("lens_members", count),
+		fmt.Sprintf("- [%s](%s)", filepath.Base(family.Uri), family.Uri),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getMemberDocumentation returns parents, partners, count of children, aliases and biography excerpt of person
+func getMemberDocumentation(mem *Member) string {
+	origin := mem.GetOrigin()
+	p := origin.Person
+
+	lines := []string{
+		fmt.Sprintf("**%s %s**", formatNames(origin.Name, origin.Aliases), origin.GetSurname()),
+		"",
+	}
+
+	if p.IsChild {
+		lines = append(lines, "- "+L("child_of_source", p.Relation.Sources.Format()))
+	}
+
+	var partners []string
+
+	for partner := range root.PartnersIter(origin) {
+		partners = append(partners, partner.Name+" "+partner.GetSurname())
+	}
+
+	if len(partners) > 0 {
+		lines = append(lines, "- "+L("completion_partners", strings.Join(partners, ", ")))
+	}
+
+	count := 0
+
+	for range root.ChildrenIter(origin) {
+		count++
+	}
+
+	lines = append(lines, "- "+L("completion_children", count))
+
+	if origin.InfoUri != "" {
+		text, err := GetText(origin.InfoUri)
+
+		if err == nil {
+			if excerpt := GetMarkdownExcerpt(text, 300); excerpt != "" {
+				lines = append(lines, "", excerpt)
+			}
+		}
+
+		lines = append(lines, "", fmt.Sprintf("[%s](%s)", L("lens_info"), origin.InfoUri))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getSnippets returns snippets of a new family, a new relation and the next child
+// when the line before the cursor is empty
+func getSnippets(doc *Doc, pos Position) (list []proto.CompletionItem) {
+	if doc == nil {
+		return
+	}
+
+	line := int(pos.Line)
+
+	if strings.TrimSpace(Slice(doc.GetTextByLine(line), 0, int(pos.Character))) != "" {
+		return
+	}
+
+	snippets := isSnippetSupported()
+
+	add := func(label string, sort string, text string) {
+		format := proto.InsertTextFormatSnippet
+
+		// client without snippets gets placeholders as plain text
+		if !snippets {
+			text = snippetPlaceholderRegexp.ReplaceAllString(text, "$1")
+			format = proto.InsertTextFormatPlainText
+		}
+
+		list = append(list, proto.CompletionItem{
+			Kind:             new(proto.CompletionItemKindSnippet),
+			Label:            label,
+			SortText:         new(sort),
+			InsertText:       new(text),
+			InsertTextFormat: new(format),
+		})
+	}
+
+	prev := ""
+
+	if line > 0 {
+		prev = strings.TrimSpace(doc.GetTextByLine(line - 1))
+	}
+
+	if match := childNumRegexp.FindStringSubmatch(prev); match != nil {
+		num, _ := strconv.Atoi(match[1])
+		add(L("snippet_child", num+1), "0", fmt.Sprintf("%d. ${1:Name}", num+1))
+	}
+
+	add(L("snippet_relation"), "01", "${1:Name} + ${2:Name Surname} =\n1. ${3}")
+
+	if prev == "" {
+		add(L("snippet_family"), "02", "${1:Surname}\n\n${0}")
+	}
+
+	return
+}
+
+var childNumRegexp = regexp.MustCompile(`^(\d+)\p{L}*\.`)
+
+var snippetPlaceholderRegexp = regexp.MustCompile(`\$\{\d+:?([^}]*)}`)
+
+func isSnippetSupported() bool {
+	d := clientCapabilities.TextDocument
+
+	if d == nil || d.Completion == nil || d.Completion.CompletionItem == nil {
+		return false
+	}
+
+	support := d.Completion.CompletionItem.SnippetSupport
+
+	return support != nil && *support
+}
+
+// getMembersWithPartners returns members which are sources of relations with other sources
+func getMembersWithPartners() map[*Member]bool {
+	res := make(map[*Member]bool)
+
+	for ref := range root.RefsIter() {
+		p := ref.Person
+
+		if ref.Member == nil || p == nil || p.Side != fm.SideSources || len(p.Relation.Sources.Persons) < 2 {
+			continue
+		}
+
+		res[ref.Member.GetOrigin()] = true
+	}
+
+	return res
+}
+
 // GetCompletionType
 // "= |", []
+// "+ |", []
+// "+ name", [string]
 // "name| surname", [string, string]
 // "name |", [string]
 // "| surname", [string]
@@ -177,6 +476,10 @@ func GetCompletionType(uri Uri, pos Position) (t string, words []string, err err
 		return "| surname", []string{next.Text}, nil
 	}
 
+	if blank && prev != nil && prev.SubType == fm.TokenPlus {
+		return "+ |", []string{}, nil
+	}
+
 	if token.Type == fm.TokenWord && prev != nil && prev.SubType == fm.TokenEqual {
 		return "= label|", []string{prev.Text}, nil
 	}
@@ -185,6 +488,10 @@ func GetCompletionType(uri Uri, pos Position) (t string, words []string, err err
 		return "name surname|", []string{prev.Text, token.Text}, nil
 	}
 
+	if token.Type == fm.TokenName && prev != nil && prev.SubType == fm.TokenPlus {
+		return "+ name", []string{token.Text}, nil
+	}
+
 	if token.Type == fm.TokenName {
 		return "name", []string{token.Text}, nil
 	}
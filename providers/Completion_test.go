@@ -0,0 +1,63 @@
+package providers
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestGetSnippets(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n\n",
+	})
+
+	doc := GetDoc(folder + "/Potter.fml")
+
+	defer func() {
+		clientCapabilities = proto.ClientCapabilities{}
+	}()
+
+	texts := func() (res []string) {
+		for _, item := range getSnippets(doc, Position{Line: 4, Character: 0}) {
+			res = append(res, *item.InsertText)
+
+			if snippets := isSnippetSupported(); (*item.InsertTextFormat == proto.InsertTextFormatSnippet) != snippets {
+				t.Errorf("format of %q", *item.InsertText)
+			}
+		}
+
+		return
+	}
+
+	res := texts()
+
+	if len(res) != 2 || res[0] != "2. Name" || res[1] != "Name + Name Surname =\n1. " {
+		t.Errorf("plain text: %q", res)
+	}
+
+	err := json.Unmarshal([]byte(`{"textDocument": {"completion": {"completionItem": {"snippetSupport": true}}}}`), &clientCapabilities)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res = texts()
+
+	if len(res) != 2 || res[0] != "2. ${1:Name}" {
+		t.Errorf("snippets: %q", res)
+	}
+}
+
+func TestGetSnippetsAfterLetterNumber(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2a. Rose\n\n",
+	})
+
+	list := getSnippets(GetDoc(folder+"/Potter.fml"), Position{Line: 5, Character: 0})
+
+	if len(list) == 0 || *list[0].InsertText != "3. Name" {
+		t.Errorf("snippets: %v", list)
+	}
+}
@@ -2,16 +2,90 @@ package providers
 
 import (
 	"encoding/json"
+	"maps"
+	"slices"
 
 	"github.com/mitchellh/mapstructure"
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	proto "github.com/tliron/glsp/protocol_3_16"
 )
 
-func ConfigurationChange(_ *Ctx, config *ClientConfiguration) (err error) {
-	if config.Locale != "" {
-		err = SetLocale(config.Locale)
+func ConfigurationChange(ctx *Ctx, config *ClientConfiguration) (err error) {
+	if !slices.Equal(root.Exclude, config.Exclude) {
+		root.Exclude = config.Exclude
+
+		showConfigError(ctx, root.SetFolders(slices.Collect(maps.Keys(root.Folders))))
+	}
+
+	return setClientConfiguration(*config)
+}
+
+// ConfigFilesChange reloads settings and files of workspace folders when their config file is created, changed or deleted
+func ConfigFilesChange(ctx *Ctx, params *proto.DidChangeWatchedFilesParams) (err error) {
+	changed := false
+
+	for _, event := range params.Changes {
+		uri := NormalizeUri(event.URI)
+		folder := root.FindFolder(uri)
+
+		if folder != "" && toFolderUri(folder)+ConfigFileName == uri {
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	showConfigError(ctx, root.SetFolders(slices.Collect(maps.Keys(root.Folders))))
+
+	return setClientConfiguration(clientConfig)
+}
+
+// watchConfigFiles registers watcher of config files, should be called in goroutine
+func watchConfigFiles(ctx *Ctx) {
+	ctx.Call(proto.ServerClientRegisterCapability, proto.RegistrationParams{
+		Registrations: []proto.Registration{
+			{
+				ID:     "familymarkup-config-files",
+				Method: proto.MethodWorkspaceDidChangeWatchedFiles,
+				RegisterOptions: proto.DidChangeWatchedFilesRegistrationOptions{
+					Watchers: []proto.FileSystemWatcher{
+						{GlobPattern: "**/" + ConfigFileName},
+					},
+				},
+			},
+		},
+	}, nil)
+}
+
+func isConfigWatchSupported() bool {
+	w := clientCapabilities.Workspace
+
+	return w != nil && w.DidChangeWatchedFiles != nil && w.DidChangeWatchedFiles.DynamicRegistration != nil && *w.DidChangeWatchedFiles.DynamicRegistration
+}
+
+// showConfigError shows error of reading config files, folders with wrong config use default settings
+func showConfigError(ctx *Ctx, err error) {
+	if err == nil || ctx == nil {
+		return
 	}
 
-	warnChildrenWithoutRelations = config.WarnChildrenWithoutRelations
+	ctx.Notify(proto.ServerWindowShowMessage, proto.ShowMessageParams{
+		Type:    proto.MessageTypeError,
+		Message: err.Error(),
+	})
+}
+
+func setClientConfiguration(config ClientConfiguration) (err error) {
+	clientConfig = config
+
+	locale := getLocale()
+
+	if locale != "" {
+		err = SetLocale(locale)
+	}
 
 	for _, doc := range root.Docs {
 		doc.NeedDiagnostic = true
@@ -20,9 +94,39 @@ func ConfigurationChange(_ *Ctx, config *ClientConfiguration) (err error) {
 	return
 }
 
+// getLocale returns locale from workspace config file if there is one, otherwise from client configuration.
+// Locale is global for the server, so with several workspace folders the first one (by uri) with locale is used
+func getLocale() string {
+	folders := make([]Uri, 0, len(root.Configs))
+
+	for folder := range root.Configs {
+		folders = append(folders, folder)
+	}
+
+	slices.Sort(folders)
+
+	for _, folder := range folders {
+		if locale := root.Configs[folder].Locale; locale != "" {
+			return locale
+		}
+	}
+
+	return clientConfig.Locale
+}
+
+func isWarnChildrenWithoutRelations(uri Uri) bool {
+	if warn := root.GetConfig(uri).WarnChildrenWithoutRelations; warn != nil {
+		return *warn
+	}
+
+	return clientConfig.WarnChildrenWithoutRelations
+}
+
 type ClientConfiguration struct {
-	Locale                       string `json:"locale" mapstructure:"locale"`
-	WarnChildrenWithoutRelations bool   `json:"warnChildrenWithoutRelations" mapstructure:"warnChildrenWithoutRelations"`
+	Locale                       string            `json:"locale" mapstructure:"locale"`
+	WarnChildrenWithoutRelations bool              `json:"warnChildrenWithoutRelations" mapstructure:"warnChildrenWithoutRelations"`
+	Exclude                      []string          `json:"exclude" mapstructure:"exclude"`
+	Diagnostics                  map[string]string `json:"diagnostics" mapstructure:"diagnostics"`
 }
 
 func GetClientConfiguration(src any) (res ClientConfiguration, err error) {
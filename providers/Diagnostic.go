@@ -2,6 +2,10 @@ package providers
 
 import (
 	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
 
 	. "github.com/redexp/familymarkup-lsp/state"
 	. "github.com/redexp/familymarkup-lsp/types"
@@ -21,8 +25,56 @@ const (
 	UnknownPersonError
 	NameDuplicateWarning
 	ChildWithoutRelationsInfo
+	SyntaxError
+	OrphanFileWarning
+	SimilarPersonHint
 )
 
+// DiagnosticNames used as keys in "diagnostics" section of configuration
+var DiagnosticNames = map[uint8]string{
+	SyntaxError:               "syntax-error",
+	UnknownFamilyError:        "unknown-family",
+	UnknownPersonError:        "unknown-person",
+	NameDuplicateWarning:      "duplicate-name",
+	ChildWithoutRelationsInfo: "child-without-relations",
+	OrphanFileWarning:         "orphan-file",
+	SimilarPersonHint:         "similar-person",
+}
+
+// DiagnosticCodes are stable codes of diagnostics described in docs/diagnostics.md
+var DiagnosticCodes = map[uint8]string{
+	SyntaxError:               "FML001",
+	UnknownFamilyError:        "FML002",
+	UnknownPersonError:        "FML003",
+	NameDuplicateWarning:      "FML004",
+	ChildWithoutRelationsInfo: "FML005",
+	OrphanFileWarning:         "FML006",
+	SimilarPersonHint:         "FML007",
+}
+
+const DiagnosticSource = "familymarkup"
+
+const DiagnosticsDocUrl = "https://github.com/redexp/familymarkup-lsp/blob/main/docs/diagnostics.md"
+
+var defaultSeverities = map[uint8]proto.DiagnosticSeverity{
+	SyntaxError:               proto.DiagnosticSeverityError,
+	UnknownFamilyError:        proto.DiagnosticSeverityError,
+	UnknownPersonError:        proto.DiagnosticSeverityError,
+	NameDuplicateWarning:      proto.DiagnosticSeverityWarning,
+	ChildWithoutRelationsInfo: proto.DiagnosticSeverityInformation,
+	OrphanFileWarning:         proto.DiagnosticSeverityWarning,
+	SimilarPersonHint:         proto.DiagnosticSeverityHint,
+}
+
+const SeverityOff = "off"
+
+var severityNames = map[string]proto.DiagnosticSeverity{
+	"error":   proto.DiagnosticSeverityError,
+	"warning": proto.DiagnosticSeverityWarning,
+	"info":    proto.DiagnosticSeverityInformation,
+	"hint":    proto.DiagnosticSeverityHint,
+}
+
 func TextDocumentDiagnostic(_ *Ctx, params *DocumentDiagnosticParams) (res *DocumentDiagnosticReport, err error) {
 	err = root.UpdateDirty()
 
@@ -33,6 +85,14 @@ func TextDocumentDiagnostic(_ *Ctx, params *DocumentDiagnosticParams) (res *Docu
 	uri := NormalizeUri(params.TextDocument.URI)
 	doc := GetDoc(uri)
 
+	if doc == nil {
+		res = &DocumentDiagnosticReport{
+			Kind:  "full",
+			Items: GetOrphanDiagnostics(uri),
+		}
+		return
+	}
+
 	if !doc.NeedDiagnostic {
 		res = &DocumentDiagnosticReport{
 			Kind:     "unchanged",
@@ -52,7 +112,7 @@ func TextDocumentDiagnostic(_ *Ctx, params *DocumentDiagnosticParams) (res *Docu
 	return
 }
 
-func WorkspaceDiagnostic(_ *Ctx, _ *WorkspaceDiagnosticParams) (res *WorkspaceDiagnosticReport, err error) {
+func WorkspaceDiagnostic(_ *Ctx, params *WorkspaceDiagnosticParams) (res *WorkspaceDiagnosticReport, err error) {
 	err = root.UpdateDirty()
 
 	if err != nil {
@@ -88,6 +148,8 @@ func WorkspaceDiagnostic(_ *Ctx, _ *WorkspaceDiagnosticParams) (res *WorkspaceDi
 		i++
 	}
 
+	res.Items = append(res.Items, getOrphanReports(params.PreviousResultIds)...)
+
 	return
 }
 
@@ -100,21 +162,37 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 		return
 	}
 
-	add := func(item proto.Diagnostic) {
+	suppressions := getSuppressions(doc.Text)
+
+	add := func(t uint8, item proto.Diagnostic) {
+		if isSuppressed(suppressions, t, item.Range.Start.Line) {
+			return
+		}
+
+		severity, ok := getDiagnosticSeverity(uri, t)
+
+		if !ok {
+			return
+		}
+
+		code := DiagnosticCodes[t]
+
+		item.Severity = &severity
+		item.Source = new(DiagnosticSource)
+		item.Code = &proto.IntegerOrString{Value: code}
+		item.CodeDescription = &proto.CodeDescription{
+			HRef: DiagnosticsDocUrl + "#" + strings.ToLower(code),
+		}
+
 		list = append(list, item)
 	}
 
-	Error := new(proto.DiagnosticSeverityError)
-	Warning := new(proto.DiagnosticSeverityWarning)
-	Info := new(proto.DiagnosticSeverityInformation)
-
 	// syntax errors
 	for _, token := range doc.Tokens {
 		if token.Type == fm.TokenInvalid || token.ErrType == fm.ErrUnexpected {
-			add(proto.Diagnostic{
-				Severity: Error,
-				Range:    TokenToRange(token),
-				Message:  L("syntax_error"),
+			add(SyntaxError, proto.Diagnostic{
+				Range:   TokenToRange(token),
+				Message: L("syntax_error"),
 			})
 		}
 	}
@@ -128,6 +206,7 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 		var t uint8
 		var loc fm.Loc
 		var message string
+		var family *Family
 
 		p := ref.Person
 
@@ -141,26 +220,41 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 			t = UnknownPersonError
 			loc = p.Name.Loc()
 			message = L("unknown_person", p.Name.Text)
+			family = ref.Family
 
 		case RefTypeNameSurname:
-			f := root.FindFamily(p.Surname.Text)
+			family = root.FindFamily(p.Surname.Text)
 
-			if f == nil {
+			if family == nil {
 				continue
 			}
 
 			t = UnknownPersonError
 			loc = p.Name.Loc()
-			message = L("unknown_person_in_family", f.Name, p.Name.Text)
+			message = L("unknown_person_in_family", family.Name, p.Name.Text)
 
 		default:
 			continue
 		}
 
-		add(proto.Diagnostic{
-			Severity: Error,
-			Range:    LocToRange(loc),
-			Message:  message,
+		var related []proto.DiagnosticRelatedInformation
+
+		if family != nil {
+			related = []proto.DiagnosticRelatedInformation{
+				{
+					Location: proto.Location{
+						URI:   family.Uri,
+						Range: TokenToRange(family.Node.Name),
+					},
+					Message: L("family_declaration", family.Name),
+				},
+			}
+		}
+
+		add(t, proto.Diagnostic{
+			Range:              LocToRange(loc),
+			Message:            message,
+			RelatedInformation: related,
 			Data: DiagnosticData{
 				Type: t,
 			},
@@ -211,8 +305,7 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 
 				ensureLocations(family, member, dups)
 
-				add(proto.Diagnostic{
-					Severity:           Warning,
+				add(NameDuplicateWarning, proto.Diagnostic{
 					Range:              TokenToRange(p.Name),
 					Message:            L("duplicate_count_of_name", len(locations), name),
 					RelatedInformation: locations,
@@ -226,17 +319,16 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 		}
 	}
 
-	if warnChildrenWithoutRelations {
+	if _, ok := getDiagnosticSeverity(uri, ChildWithoutRelationsInfo); ok {
 		for f := range root.FamiliesByUriIter(uri) {
 			for mem := range f.MembersIter() {
 				if !mem.Person.IsChild || mem.HasRef() {
 					continue
 				}
 
-				add(proto.Diagnostic{
-					Severity: Info,
-					Range:    TokenToRange(mem.Person.Name),
-					Message:  L("child_without_relations", mem.Name, mem.Family.Name),
+				add(ChildWithoutRelationsInfo, proto.Diagnostic{
+					Range:   TokenToRange(mem.Person.Name),
+					Message: L("child_without_relations", mem.Name, mem.Family.Name),
 					Data: DiagnosticData{
 						Type: ChildWithoutRelationsInfo,
 					},
@@ -245,9 +337,108 @@ func GetDiagnostics(uri Uri) (list []proto.Diagnostic) {
 		}
 	}
 
+	if _, ok := getDiagnosticSeverity(uri, SimilarPersonHint); ok {
+		similar := root.FindSimilarMembers()
+
+		for f := range root.FamiliesByUriIter(uri) {
+			for mem := range f.MembersIter() {
+				list := similar[mem]
+
+				if len(list) == 0 {
+					continue
+				}
+
+				related := make([]proto.DiagnosticRelatedInformation, len(list))
+
+				for i, item := range list {
+					related[i] = proto.DiagnosticRelatedInformation{
+						Location: proto.Location{
+							URI:   item.Family.Uri,
+							Range: TokenToRange(item.Person.Name),
+						},
+						Message: item.Name + " " + item.GetSurname(),
+					}
+				}
+
+				add(SimilarPersonHint, proto.Diagnostic{
+					Range:              TokenToRange(mem.Person.Name),
+					Message:            L("similar_person", mem.Name, mem.GetSurname(), list[0].Name, list[0].GetSurname()),
+					RelatedInformation: related,
+					Data: DiagnosticData{
+						Type:    SimilarPersonHint,
+						Surname: f.Name,
+						Name:    mem.Name,
+					},
+				})
+			}
+		}
+	}
+
 	return
 }
 
+// getDiagnosticSeverity returns severity from configuration of uri folder or from client configuration
+// and false if diagnostic is turned off
+func getDiagnosticSeverity(uri Uri, t uint8) (severity proto.DiagnosticSeverity, ok bool) {
+	name := DiagnosticNames[t]
+	value, exist := root.GetConfig(uri).Diagnostics[name]
+
+	if !exist {
+		value, exist = clientConfig.Diagnostics[name]
+	}
+
+	if !exist && t == ChildWithoutRelationsInfo && !isWarnChildrenWithoutRelations(uri) {
+		return
+	}
+
+	// analysis of the whole tree is optional
+	if !exist && t == SimilarPersonHint {
+		return
+	}
+
+	if value == SeverityOff {
+		return
+	}
+
+	severity, ok = severityNames[value]
+
+	if !ok {
+		severity, ok = defaultSeverities[t]
+	}
+
+	return
+}
+
+const SuppressComment = "fml-ignore"
+
+var suppressRegexp = regexp.MustCompile(`^\s*//\s*` + SuppressComment + `(?:\s+(.*))?$`)
+
+// getSuppressions returns lines with names of suppressed diagnostics by comment like
+// "// fml-ignore duplicate-name" on the preceding line. Empty list means all diagnostics
+func getSuppressions(text string) (res map[uint32][]string) {
+	res = make(map[uint32][]string)
+
+	for i, line := range strings.Split(text, "\n") {
+		match := suppressRegexp.FindStringSubmatch(strings.TrimRight(line, "\r"))
+
+		if match == nil {
+			continue
+		}
+
+		res[uint32(i+1)] = strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+
+	return
+}
+
+func isSuppressed(suppressions map[uint32][]string, t uint8, line uint32) bool {
+	names, ok := suppressions[line]
+
+	return ok && (len(names) == 0 || slices.Contains(names, DiagnosticNames[t]))
+}
+
 type DiagnosticData struct {
 	Type    uint8  `json:"type"`
 	Surname string `json:"surname"`
@@ -0,0 +1,67 @@
+package providers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetSuppressions(t *testing.T) {
+	text := "Potter\n\n// fml-ignore duplicate-name\nJames + Lily =\n  //fml-ignore\n1. Harry\n// fml-ignore unknown-person, unknown-family\r\nRon Weasley\n// fml-ignored\n"
+
+	res := getSuppressions(text)
+
+	if len(res) != 3 {
+		t.Fatalf("res: %v", res)
+	}
+
+	if !slices.Equal(res[3], []string{"duplicate-name"}) {
+		t.Errorf("line 3: %v", res[3])
+	}
+
+	if names, ok := res[5]; !ok || len(names) != 0 {
+		t.Errorf("line 5: %v", names)
+	}
+
+	if !slices.Equal(res[7], []string{"unknown-person", "unknown-family"}) {
+		t.Errorf("line 7: %v", res[7])
+	}
+
+	if !isSuppressed(res, NameDuplicateWarning, 3) || isSuppressed(res, UnknownPersonError, 3) {
+		t.Error("line 3 suppression")
+	}
+
+	if !isSuppressed(res, SyntaxError, 5) || isSuppressed(res, SyntaxError, 4) {
+		t.Error("line 5 suppression")
+	}
+}
+
+func TestOrphanDiagnostics(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml":           "Potter\n\nJames + Lily =\n1. Harry\n",
+		"Potter/Harri.md":      "# Harry\n",
+		"docs/notes/README.md": "# Notes\n",
+	})
+
+	if list := GetOrphanDiagnostics(folder + "/Potter/Harri.md"); len(list) != 1 {
+		t.Errorf("unknown member: %v", list)
+	}
+
+	if list := GetOrphanDiagnostics(folder + "/docs/notes/README.md"); len(list) != 0 {
+		t.Errorf("unknown family without info folder: %v", list)
+	}
+
+	folder = testFolder(t, map[string]string{
+		".familymarkup.json":   `{"info": {"folder": "bio"}}`,
+		"Potter.fml":           "Potter\n\nJames + Lily =\n1. Harry\n",
+		"bio/Weasley/Ron.md":   "# Ron\n",
+		"docs/notes/README.md": "# Notes\n",
+	})
+
+	if list := GetOrphanDiagnostics(folder + "/bio/Weasley/Ron.md"); len(list) != 1 {
+		t.Errorf("unknown family in info folder: %v", list)
+	}
+
+	if list := GetOrphanDiagnostics(folder + "/docs/notes/README.md"); len(list) != 0 {
+		t.Errorf("file out of info folder: %v", list)
+	}
+}
@@ -0,0 +1,204 @@
+package providers
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	fm "github.com/redexp/familymarkup-parser"
+)
+
+var gedcomMonths = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// ExportGedcom returns all families of workspace in GEDCOM 5.5.1 format.
+// FamilyMarkup has no gender, so the first source of relation is HUSB and the second one is WIFE
+func ExportGedcom() string {
+	type Fam struct {
+		Id       string
+		Partners []*Member
+		Children []*Member
+	}
+
+	persons := make([]*Member, 0)
+	ids := make(map[*Member]string)
+
+	for mem := range root.MembersIter() {
+		origin := mem.GetOrigin()
+
+		if _, ok := ids[origin]; !ok {
+			ids[origin] = ""
+			persons = append(persons, origin)
+		}
+	}
+
+	slices.SortFunc(persons, func(a, b *Member) int {
+		return strings.Compare(gedcomKey(a), gedcomKey(b))
+	})
+
+	for i, mem := range persons {
+		ids[mem] = fmt.Sprintf("@I%d@", i+1)
+	}
+
+	var families []*Fam
+	famByKey := make(map[string]*Fam)
+	famc := make(map[*Member][]string)
+	fams := make(map[*Member][]string)
+
+	getMember := func(uri string, p *fm.Person) *Member {
+		if p.Name == nil {
+			return nil
+		}
+
+		mem := root.GetMemberByToken(uri, p.Name)
+
+		if mem == nil {
+			return nil
+		}
+
+		return mem.GetOrigin()
+	}
+
+	for f, doc := range root.FmFamilyIter() {
+		for _, rel := range f.Relations {
+			var partners, children []*Member
+
+			for _, p := range rel.Sources.Persons {
+				if mem := getMember(doc.Uri, p); mem != nil && !slices.Contains(partners, mem) {
+					partners = append(partners, mem)
+				}
+			}
+
+			if rel.Targets != nil {
+				for _, p := range rel.Targets.Persons {
+					if mem := getMember(doc.Uri, p); mem != nil {
+						children = append(children, mem)
+					}
+				}
+			}
+
+			if len(partners) == 0 || (len(partners) == 1 && len(children) == 0) {
+				continue
+			}
+
+			keys := make([]string, len(partners))
+
+			for i, mem := range partners {
+				keys[i] = ids[mem]
+			}
+
+			slices.Sort(keys)
+			key := strings.Join(keys, "+")
+
+			fam, ok := famByKey[key]
+
+			if !ok {
+				fam = &Fam{
+					Id:       fmt.Sprintf("@F%d@", len(families)+1),
+					Partners: partners,
+				}
+
+				famByKey[key] = fam
+				families = append(families, fam)
+
+				for _, mem := range partners {
+					fams[mem] = append(fams[mem], fam.Id)
+				}
+			}
+
+			for _, mem := range children {
+				if slices.Contains(fam.Children, mem) {
+					continue
+				}
+
+				fam.Children = append(fam.Children, mem)
+				famc[mem] = append(famc[mem], fam.Id)
+			}
+		}
+	}
+
+	var s strings.Builder
+
+	line := func(level int, tag string, value string) {
+		if value == "" {
+			s.WriteString(fmt.Sprintf("%d %s\n", level, tag))
+		} else {
+			s.WriteString(fmt.Sprintf("%d %s %s\n", level, tag, value))
+		}
+	}
+
+	line(0, "HEAD", "")
+	line(1, "SOUR", "familymarkup")
+	line(1, "GEDC", "")
+	line(2, "VERS", "5.5.1")
+	line(2, "FORM", "LINEAGE-LINKED")
+	line(1, "CHAR", "UTF-8")
+
+	for _, mem := range persons {
+		s.WriteString(fmt.Sprintf("0 %s INDI\n", ids[mem]))
+		line(1, "NAME", fmt.Sprintf("%s /%s/", mem.Name, mem.GetSurname()))
+
+		for _, alias := range mem.Aliases {
+			line(2, "NICK", alias)
+		}
+
+		if date := gedcomDate(mem.GetBirthDate()); date != "" {
+			line(1, "BIRT", "")
+			line(2, "DATE", date)
+		}
+
+		for _, id := range famc[mem] {
+			line(1, "FAMC", id)
+		}
+
+		for _, id := range fams[mem] {
+			line(1, "FAMS", id)
+		}
+	}
+
+	for _, fam := range families {
+		s.WriteString(fmt.Sprintf("0 %s FAM\n", fam.Id))
+
+		for i, mem := range fam.Partners {
+			switch i {
+			case 0:
+				line(1, "HUSB", ids[mem])
+			case 1:
+				line(1, "WIFE", ids[mem])
+			}
+		}
+
+		for _, mem := range fam.Children {
+			line(1, "CHIL", ids[mem])
+		}
+	}
+
+	line(0, "TRLR", "")
+
+	return s.String()
+}
+
+func gedcomKey(mem *Member) string {
+	return mem.GetSurname() + "/" + mem.Name + "/" + mem.Family.Uri
+}
+
+// gedcomDate converts date YYYYMMDD to GEDCOM format like "31 JUL 1980"
+func gedcomDate(date int) string {
+	if date == 0 {
+		return ""
+	}
+
+	year := date / 10000
+	month := date / 100 % 100
+	day := date % 100
+
+	if month < 1 || month > 12 {
+		return fmt.Sprintf("%d", year)
+	}
+
+	if day == 0 {
+		return fmt.Sprintf("%s %d", gedcomMonths[month-1], year)
+	}
+
+	return fmt.Sprintf("%d %s %d", day, gedcomMonths[month-1], year)
+}
@@ -0,0 +1,18 @@
+package providers
+
+import "testing"
+
+func TestGedcomDate(t *testing.T) {
+	list := map[int]string{
+		0:        "",
+		19800731: "31 JUL 1980",
+		19800700: "JUL 1980",
+		19800000: "1980",
+	}
+
+	for date, text := range list {
+		if res := gedcomDate(date); res != text {
+			t.Errorf("%d: %q != %q", date, res, text)
+		}
+	}
+}
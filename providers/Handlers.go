@@ -34,6 +34,12 @@ func CreateRequestHandler() *RequestHandler {
 				Families: SvgFamilies,
 				Path:     SvgPath,
 			},
+			&StatsHandlers{
+				Workspace: WorkspaceStats,
+			},
+			&QueryHandlers{
+				Run: RunQuery,
+			},
 		},
 	}
 }
@@ -46,16 +52,20 @@ func NewProtocolHandlers() *proto.Handler {
 		CancelRequest:                       CancelRequest,
 		TextDocumentSemanticTokensFull:      SemanticTokensFull,
 		TextDocumentSemanticTokensFullDelta: SemanticTokensDelta,
+		TextDocumentSemanticTokensRange:     SemanticTokensRange,
 		TextDocumentDidOpen:                 DocOpen,
 		TextDocumentDidChange:               DocChange,
 		TextDocumentDidClose:                DocClose,
 		WorkspaceDidCreateFiles:             DocCreate,
 		WorkspaceDidRenameFiles:             DocRename,
 		WorkspaceDidDeleteFiles:             DocDelete,
+		WorkspaceDidChangeWatchedFiles:      ConfigFilesChange,
 		TextDocumentCompletion:              Completion,
+		CompletionItemResolve:               CompletionResolve,
 		TextDocumentDefinition:              Definition,
 		TextDocumentReferences:              References,
 		TextDocumentTypeDefinition:          TypeDefinition,
+		TextDocumentImplementation:          Implementation,
 		TextDocumentHover:                   Hover,
 		TextDocumentDocumentHighlight:       DocumentHighlight,
 		TextDocumentPrepareRename:           PrepareRename,
@@ -67,6 +77,11 @@ func NewProtocolHandlers() *proto.Handler {
 		TextDocumentRangeFormatting:         RangeFormating,
 		TextDocumentOnTypeFormatting:        LineFormating,
 		CodeActionResolve:                   CodeActionResolve,
+		TextDocumentLinkedEditingRange:      LinkedEditingRange,
+		TextDocumentSelectionRange:          SelectionRange,
+		TextDocumentCodeLens:                CodeLens,
+		CodeLensResolve:                     CodeLensResolve,
+		WorkspaceExecuteCommand:             ExecuteCommand,
 	}
 }
 
@@ -85,6 +100,11 @@ func (req *RequestHandler) RpcHandle(c context.Context, conn *jsonrpc2.Conn, r *
 		Notify: func(method string, params any) {
 			_ = conn.Notify(c, method, params)
 		},
+		// handlers are called synchronously while reading messages,
+		// so Call should be used only in a goroutine otherwise it will wait for response forever
+		Call: func(method string, params any, result any) {
+			_ = conn.Call(c, method, params, result)
+		},
 	}
 
 	if r.Params != nil {
@@ -0,0 +1,26 @@
+package providers
+
+import (
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+// Implementation returns all relations where person is a source: marriages and lists of children in all files
+func Implementation(_ *Ctx, params *proto.ImplementationParams) (res any, err error) {
+	ref, err := getDefinition(params.TextDocument.URI, params.Position)
+
+	if err != nil || ref == nil || ref.Member == nil {
+		return
+	}
+
+	list := make([]proto.Location, 0)
+
+	for p, uri := range root.SourceRelationsIter(ref.Member) {
+		list = append(list, proto.Location{
+			URI:   uri,
+			Range: LocToRange(p.Relation.Loc),
+		})
+	}
+
+	return list, nil
+}
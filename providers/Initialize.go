@@ -5,23 +5,36 @@ import (
 	"strings"
 
 	. "github.com/redexp/familymarkup-lsp/state"
-	. "github.com/redexp/familymarkup-lsp/utils"
 	proto "github.com/tliron/glsp/protocol_3_16"
 )
 
-func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
+func Initialize(ctx *Ctx, params *proto.InitializeParams) (any, error) {
 	root = CreateRoot()
+	clientCapabilities = params.Capabilities
 
-	options, err := GetClientConfiguration(params.InitializationOptions)
+	options, optionsErr := GetClientConfiguration(params.InitializationOptions)
 
-	if err == nil {
-		err = SetLocale(options.Locale)
+	if optionsErr == nil {
+		root.Exclude = options.Exclude
+	}
+
+	if params.WorkspaceFolders != nil {
+		folders := make([]string, len(params.WorkspaceFolders))
+
+		for i, folder := range params.WorkspaceFolders {
+			folders[i] = NormalizeUri(folder.URI)
+		}
+
+		// wrong config file should not stop the server, its folder uses default settings
+		showConfigError(ctx, root.SetFolders(folders))
+	}
+
+	if optionsErr == nil {
+		err := setClientConfiguration(options)
 
 		if err != nil {
 			return nil, err
 		}
-
-		warnChildrenWithoutRelations = options.WarnChildrenWithoutRelations
 	}
 
 	fileFilters := proto.FileOperationRegistrationOptions{
@@ -29,7 +42,7 @@ func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
 			{
 				Scheme: new("file"),
 				Pattern: proto.FileOperationPattern{
-					Glob: fmt.Sprintf("**/*.{%s}", strings.Join(AllExt, ",")),
+					Glob: fmt.Sprintf("**/*.{%s}", strings.Join(root.AllExt(), ",")),
 				},
 			},
 			{
@@ -56,13 +69,15 @@ func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
 				"full": obj{
 					"delta": true,
 				},
-				"range": false,
+				"range": true,
 				"legend": obj{
 					"tokenTypes":     Legend.Types,
 					"tokenModifiers": Legend.Modifiers,
 				},
 			},
-			"completionProvider": obj{},
+			"completionProvider": obj{
+				"resolveProvider": true,
+			},
 			"workspace": obj{
 				"workspaceFolders": obj{
 					"supported": true,
@@ -73,13 +88,16 @@ func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
 					"didDelete": fileFilters,
 				},
 			},
-			"definitionProvider":        true,
-			"referencesProvider":        true,
-			"typeDefinitionProvider":    true,
-			"hoverProvider":             true,
-			"documentHighlightProvider": true,
-			"foldingRangeProvider":      true,
-			"documentSymbolProvider":    true,
+			"definitionProvider":         true,
+			"referencesProvider":         true,
+			"typeDefinitionProvider":     true,
+			"implementationProvider":     true,
+			"hoverProvider":              true,
+			"documentHighlightProvider":  true,
+			"linkedEditingRangeProvider": true,
+			"foldingRangeProvider":       true,
+			"selectionRangeProvider":     true,
+			"documentSymbolProvider":     true,
 			"workspaceSymbolProvider": obj{
 				"resolveProvider": true,
 			},
@@ -89,9 +107,21 @@ func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
 			"codeActionProvider": obj{
 				"codeActionKinds": []proto.CodeActionKind{
 					proto.CodeActionKindQuickFix,
+					proto.CodeActionKindRefactor,
+					proto.CodeActionKindRefactorExtract,
+					proto.CodeActionKindRefactorRewrite,
+					CodeActionKindRefactorMove,
+					CodeActionKindSourceRenumber,
+					CodeActionKindSourceSort,
 				},
 				"resolveProvider": true,
 			},
+			"executeCommandProvider": obj{
+				"commands": Commands,
+			},
+			"codeLensProvider": obj{
+				"resolveProvider": true,
+			},
 			"documentFormattingProvider":      true,
 			"documentRangeFormattingProvider": true,
 			"documentOnTypeFormattingProvider": proto.DocumentOnTypeFormattingOptions{
@@ -105,22 +135,16 @@ func Initialize(_ *Ctx, params *proto.InitializeParams) (any, error) {
 		},
 	}
 
-	if params.WorkspaceFolders != nil {
-		folders := make([]string, len(params.WorkspaceFolders))
-
-		for i, folder := range params.WorkspaceFolders {
-			folders[i] = NormalizeUri(folder.URI)
-		}
-
-		root.SetFolders(folders)
-	}
-
 	return res, nil
 }
 
-func Initialized(_ *Ctx, _ *proto.InitializedParams) (err error) {
+func Initialized(ctx *Ctx, _ *proto.InitializedParams) (err error) {
 	err = root.UpdateDirty()
 
+	if ctx != nil && isConfigWatchSupported() {
+		go watchConfigFiles(ctx)
+	}
+
 	return
 }
 
@@ -0,0 +1,39 @@
+package providers
+
+import (
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func LinkedEditingRange(_ *Ctx, params *proto.LinkedEditingRangeParams) (res *proto.LinkedEditingRanges, err error) {
+	uri := NormalizeUri(params.TextDocument.URI)
+
+	def, err := getDefinition(uri, params.Position)
+
+	if err != nil || def == nil || def.Type == RefTypeSurname || def.Member == nil || def.Person == nil {
+		return
+	}
+
+	name := def.Person.Name.Text
+	ranges := make([]proto.Range, 0)
+
+	// linked ranges should have the same text, so aliases and misspelled names are skipped
+	for ref, refUri := range def.Member.GetAllRefsIter() {
+		if refUri != uri || ref.Person == nil || ref.Person.Name.Text != name {
+			continue
+		}
+
+		ranges = append(ranges, TokenToRange(ref.Person.Name))
+	}
+
+	if len(ranges) < 2 {
+		return
+	}
+
+	res = &proto.LinkedEditingRanges{
+		Ranges: ranges,
+	}
+
+	return
+}
@@ -0,0 +1,305 @@
+package providers
+
+import (
+	"fmt"
+	"hash/fnv"
+	urlParser "net/url"
+	"path"
+	"strings"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+// modes of OrphanFileWarning quick fixes
+const (
+	RenameOrphanFile = iota
+	AddOrphanNameAlias
+)
+
+// reported Markdown files with diagnostics, used to clear diagnostics of linked files
+var orphanReported = make(UriSet)
+
+// OrphanFile is a Markdown file from Root.UnknownFiles with parsed family and member name
+type OrphanFile struct {
+	File   *File
+	Family *Family
+	Name   string
+	Index  int // index of member name in File.Path
+}
+
+func getOrphanFile(uri Uri) *OrphanFile {
+	file, ok := root.UnknownFiles[uri]
+
+	if !ok {
+		return nil
+	}
+
+	orphan := &OrphanFile{
+		File:  file,
+		Index: -1,
+	}
+
+	count := len(file.Path)
+
+	for i, part := range file.Path {
+		if orphan.Family == nil {
+			orphan.Family = root.FindFamily(part)
+			continue
+		}
+
+		// Surname/index.md is a description of family
+		if part == "index" && i == count-1 {
+			return nil
+		}
+
+		orphan.Name = part
+		orphan.Index = i
+
+		return orphan
+	}
+
+	// files in root of info folder or Surname.md
+	if orphan.Family != nil || count < 2 {
+		return nil
+	}
+
+	// by default info folder is the workspace folder with any other Markdown files like docs/README.md,
+	// so unknown family is reported only when info folder is set in config
+	if !hasInfoFolder(uri) {
+		return nil
+	}
+
+	return orphan
+}
+
+func hasInfoFolder(uri Uri) bool {
+	folder := root.GetConfig(uri).Info.Folder
+
+	return folder != "" && folder != "."
+}
+
+// FindMember returns member with the closest name which doesn't have Markdown file yet
+func (orphan *OrphanFile) FindMember() *Member {
+	if orphan.Family == nil {
+		return nil
+	}
+
+	mem := orphan.Family.FindMember(orphan.Name)
+
+	if mem == nil || mem.InfoUri != "" {
+		return nil
+	}
+
+	return mem
+}
+
+func GetOrphanDiagnostics(uri Uri) (list []proto.Diagnostic) {
+	list = make([]proto.Diagnostic, 0)
+
+	orphan := getOrphanFile(uri)
+
+	if orphan == nil {
+		return
+	}
+
+	severity, ok := getDiagnosticSeverity(uri, OrphanFileWarning)
+
+	if !ok {
+		return
+	}
+
+	var message string
+	var related []proto.DiagnosticRelatedInformation
+	data := DiagnosticData{
+		Type: OrphanFileWarning,
+		Name: orphan.Name,
+	}
+
+	if orphan.Family == nil {
+		message = L("orphan_file_family", orphan.File.Path[0])
+	} else {
+		message = L("orphan_file_member", orphan.Name, orphan.Family.Name)
+		data.Surname = orphan.Family.Name
+
+		if mem := orphan.FindMember(); mem != nil {
+			related = append(related, proto.DiagnosticRelatedInformation{
+				Location: proto.Location{
+					URI:   mem.Family.Uri,
+					Range: TokenToRange(mem.Person.Name),
+				},
+				Message: L("orphan_file_similar", mem.Name),
+			})
+		}
+	}
+
+	code := DiagnosticCodes[OrphanFileWarning]
+
+	list = append(list, proto.Diagnostic{
+		Range:              PositionToRange(Position{}),
+		Severity:           &severity,
+		Source:             new(DiagnosticSource),
+		Code:               &proto.IntegerOrString{Value: code},
+		CodeDescription:    &proto.CodeDescription{HRef: DiagnosticsDocUrl + "#" + strings.ToLower(code)},
+		Message:            message,
+		RelatedInformation: related,
+		Data:               data,
+	})
+
+	return
+}
+
+// getOrphanReports returns diagnostics of all Markdown files which are not linked to members
+// and empty reports of files which were linked or removed since last time
+func getOrphanReports(previous []PreviousResultId) (list []WorkspaceDocumentDiagnosticReport) {
+	prevIds := make(map[Uri]string)
+
+	for _, item := range previous {
+		prevIds[item.Uri] = item.Value
+	}
+
+	for uri := range root.UnknownFiles {
+		items := GetOrphanDiagnostics(uri)
+
+		if len(items) == 0 {
+			continue
+		}
+
+		orphanReported.Set(uri)
+
+		report := WorkspaceDocumentDiagnosticReport{
+			Kind:     "full",
+			Uri:      uri,
+			ResultId: getOrphanResultId(items),
+			Items:    items,
+		}
+
+		if prevIds[uri] == report.ResultId {
+			report.Kind = "unchanged"
+			report.Items = nil
+		}
+
+		list = append(list, report)
+	}
+
+	for uri := range orphanReported {
+		if _, ok := root.UnknownFiles[uri]; ok {
+			continue
+		}
+
+		orphanReported.Remove(uri)
+
+		list = append(list, WorkspaceDocumentDiagnosticReport{
+			Kind:  "full",
+			Uri:   uri,
+			Items: make([]proto.Diagnostic, 0),
+		})
+	}
+
+	return
+}
+
+func getOrphanResultId(items []proto.Diagnostic) string {
+	h := fnv.New32a()
+
+	for _, item := range items {
+		_, _ = h.Write([]byte(item.Message))
+	}
+
+	return fmt.Sprintf("orphan-%x", h.Sum32())
+}
+
+func getOrphanActions(uri Uri, d proto.Diagnostic) (list []proto.CodeAction) {
+	orphan := getOrphanFile(uri)
+
+	if orphan == nil {
+		return
+	}
+
+	mem := orphan.FindMember()
+
+	if mem == nil {
+		return
+	}
+
+	add := func(title string, mode uint8) {
+		list = append(list, proto.CodeAction{
+			Title:       title,
+			Kind:        new(proto.CodeActionKindQuickFix),
+			Diagnostics: []proto.Diagnostic{d},
+			Data: CodeActionData{
+				Uri:  uri,
+				Type: OrphanFileWarning,
+				Mod:  mode,
+				Name: mem.Name,
+			},
+		})
+	}
+
+	add(L("orphan_file_rename", mem.Name), RenameOrphanFile)
+	add(L("orphan_file_alias", orphan.Name, mem.Name), AddOrphanNameAlias)
+
+	return
+}
+
+func resolveOrphanAction(data *CodeActionData) (edit *proto.WorkspaceEdit, err error) {
+	orphan := getOrphanFile(data.Uri)
+
+	if orphan == nil {
+		return nil, fmt.Errorf("file not found")
+	}
+
+	mem := orphan.FindMember()
+
+	if mem == nil || mem.Name != data.Name {
+		return nil, fmt.Errorf("member not found")
+	}
+
+	edit = &proto.WorkspaceEdit{}
+
+	switch data.Mod {
+	case RenameOrphanFile:
+		base, err := urlParser.Parse(data.Uri)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// in case of Surname/Name/index.md rename folder of the member
+		for range len(orphan.File.Path) - 1 - orphan.Index {
+			base.Path = path.Dir(base.Path)
+		}
+
+		oldUri := base.String()
+		newUri, err := RenameUri(oldUri, mem.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		edit.DocumentChanges = []any{proto.RenameFile{
+			Kind:   "rename",
+			OldURI: oldUri,
+			NewURI: newUri,
+		}}
+
+	case AddOrphanNameAlias:
+		doc := GetDoc(mem.Family.Uri)
+		p := mem.Person
+
+		if doc == nil {
+			return nil, fmt.Errorf("document not found")
+		}
+
+		edit.Changes = map[Uri][]proto.TextEdit{
+			doc.Uri: {{
+				Range:   namesRange(doc, p.Name, p.Aliases),
+				NewText: formatNames(p.Name.Text, append(TokensToStrings(p.Aliases), orphan.Name)),
+			}},
+		}
+	}
+
+	return
+}
@@ -0,0 +1,114 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func RunQuery(_ *Ctx, params *QueryRunParams) (res []proto.Location, err error) {
+	query, err := ParseQuery(params.Query)
+
+	if err != nil {
+		return
+	}
+
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	list, err := root.RunQuery(query)
+
+	if err != nil {
+		return
+	}
+
+	res = make([]proto.Location, len(list))
+
+	for i, mem := range list {
+		res[i] = proto.Location{
+			URI:   mem.Family.Uri,
+			Range: TokenToRange(mem.Person.Name),
+		}
+	}
+
+	return
+}
+
+// PrintQuery writes persons found by query as lines "path:line:char Name Surname", used by "query" command of CLI
+func PrintQuery(text string, paths []string, w io.Writer) (err error) {
+	query, err := ParseQuery(text)
+
+	if err != nil {
+		return
+	}
+
+	err = loadFolders(paths)
+
+	if err != nil {
+		return
+	}
+
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	list, err := root.RunQuery(query)
+
+	if err != nil {
+		return
+	}
+
+	for _, mem := range list {
+		path, err := UriToPath(mem.Family.Uri)
+
+		if err != nil {
+			return err
+		}
+
+		token := mem.Person.Name
+
+		_, err = fmt.Fprintf(w, "%s:%d:%d %s %s\n", path, token.Line+1, token.Char+1, mem.Name, mem.GetSurname())
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return
+}
+
+type QueryHandlers struct {
+	Run QueryRunFunc
+}
+
+func (req *QueryHandlers) Handle(ctx *Ctx) (res any, validMethod bool, validParams bool, err error) {
+	switch ctx.Method {
+	case QueryRunMethod:
+		validMethod = true
+
+		var params QueryRunParams
+		if err = json.Unmarshal(ctx.Params, &params); err == nil {
+			validParams = true
+			res, err = req.Run(ctx, &params)
+		}
+	}
+
+	return
+}
+
+const QueryRunMethod = "query/run"
+
+type QueryRunParams struct {
+	Query string `json:"query"`
+}
+
+type QueryRunFunc func(*Ctx, *QueryRunParams) ([]proto.Location, error)
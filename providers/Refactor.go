@@ -0,0 +1,991 @@
+package providers
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	fm "github.com/redexp/familymarkup-parser"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+// types of code actions without diagnostic
+const (
+	ExtractFamilyRefactor = uint8(iota + 128)
+	MergeFamilyRefactor
+	MovePersonRefactor
+)
+
+// modes of MovePersonRefactor
+const (
+	MovePersonOnly = iota
+	MovePersonWithDescendants
+)
+
+const CodeActionKindRefactorMove = proto.CodeActionKind("refactor.move")
+
+func getRefactorActions(uri Uri, params *proto.CodeActionParams) (list []proto.CodeAction) {
+	doc := GetDoc(uri)
+
+	if doc == nil {
+		return
+	}
+
+	only := params.Context.Only
+
+	add := func(kind proto.CodeActionKind, title string, data CodeActionData) {
+		if !isKindAllowed(only, kind) {
+			return
+		}
+
+		data.Uri = uri
+
+		list = append(list, proto.CodeAction{
+			Title: title,
+			Kind:  &kind,
+			Data:  data,
+		})
+	}
+
+	family := findFamilyByHeader(doc, params.Range)
+
+	if family != nil && len(doc.Root.Families) > 1 && !IsUriName(uri, family.Name.Text) {
+		add(proto.CodeActionKindRefactorExtract, L("extract_family_file", family.Name.Text), CodeActionData{
+			Type: ExtractFamilyRefactor,
+			Line: family.Name.Line,
+		})
+	}
+
+	if family != nil {
+		for f := range getFamilyDuplicates(uri, family) {
+			add(proto.CodeActionKindRefactorRewrite, L("merge_family_into", family.Name.Text, f.Name, filepath.Base(f.Uri), f.Node.Name.Line+1), CodeActionData{
+				Type:       MergeFamilyRefactor,
+				Line:       family.Name.Line,
+				TargetUri:  f.Uri,
+				TargetLine: f.Node.Name.Line,
+			})
+		}
+	}
+
+	person := doc.FindPersonByRange(params.Range)
+
+	if person != nil && person.Name != nil && person.Unknown == nil {
+		member := root.GetMemberByToken(uri, person.Name)
+
+		if member != nil && member.GetOrigin().InfoUri == "" && isKindAllowed(only, proto.CodeActionKindRefactor) {
+			list = append(list, proto.CodeAction{
+				Title: L("create_biography", member.Name),
+				Kind:  new(proto.CodeActionKindRefactor),
+				Command: &proto.Command{
+					Title:   L("create_biography", member.Name),
+					Command: CreateBiographyCommand,
+					Arguments: []any{CommandLocation{
+						Uri:      uri,
+						Position: TokenToPosition(person.Name),
+					}},
+				},
+			})
+		}
+
+		if member != nil && member.Family.Uri == uri && member.Family.Node.Overlaps(person.Loc) {
+			modes := []uint8{MovePersonWithDescendants}
+
+			if member.Person.IsChild {
+				modes = []uint8{MovePersonOnly}
+
+				if len(getMovedRelations(doc, member, true)) > 0 {
+					modes = append(modes, MovePersonWithDescendants)
+				}
+			}
+
+			for _, f := range getMoveTargets(member) {
+				for _, mode := range modes {
+					title := L("move_person_to_family", member.Name, f.Name)
+
+					if mode == MovePersonWithDescendants && member.Person.IsChild {
+						title = L("move_person_with_descendants", member.Name, f.Name)
+					}
+
+					add(CodeActionKindRefactorMove, title, CodeActionData{
+						Type:       MovePersonRefactor,
+						Mod:        mode,
+						Name:       person.Name.Text,
+						Line:       person.Name.Line,
+						TargetUri:  f.Uri,
+						TargetLine: f.Node.Name.Line,
+					})
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func resolveRefactor(data *CodeActionData) (res *proto.CodeAction, err error) {
+	res = &proto.CodeAction{
+		Edit: &proto.WorkspaceEdit{},
+	}
+
+	doc := GetDoc(data.Uri)
+
+	if doc == nil {
+		return nil, fmt.Errorf("document not found")
+	}
+
+	switch data.Type {
+	case ExtractFamilyRefactor:
+		res.Edit.DocumentChanges, err = extractFamily(doc, data.Line)
+
+	case MergeFamilyRefactor:
+		target := GetDoc(data.TargetUri)
+
+		if target == nil {
+			return nil, fmt.Errorf("document not found")
+		}
+
+		res.Edit.Changes, err = mergeFamilies(doc, data.Line, target, data.TargetLine)
+
+	case MovePersonRefactor:
+		target := GetDoc(data.TargetUri)
+
+		if target == nil {
+			return nil, fmt.Errorf("document not found")
+		}
+
+		res.Edit.Changes, err = movePerson(doc, data.Line, data.Name, target, data.TargetLine, data.Mod == MovePersonWithDescendants)
+
+	case RenumberChildrenSource, SortChildrenByNameSource, SortChildrenByBirthSource:
+		res.Edit.Changes, err = resolveSourceAction(data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// extractFamily moves family with header on line to the file named by its surname.
+// All references are resolved by names and surnames across the workspace, so they don't need to be changed
+func extractFamily(doc *Doc, line int) (changes []any, err error) {
+	f := findFamilyByLine(doc, line)
+
+	if f == nil {
+		return nil, fmt.Errorf("family not found")
+	}
+
+	text := strings.TrimRight(doc.GetTextByLoc(f.Loc), " \t\r\n") + "\n"
+
+	changes, err = createFamilyFile(doc.Uri, f.Name.Text, text)
+
+	if err != nil {
+		return
+	}
+
+	doc.Version++
+	doc.NeedDiagnostic = true
+
+	changes = append(changes, proto.TextDocumentEdit{
+		TextDocument: proto.OptionalVersionedTextDocumentIdentifier{
+			TextDocumentIdentifier: proto.TextDocumentIdentifier{URI: doc.Uri},
+		},
+		Edits: []any{removeFamilyEdit(doc, f)},
+	})
+
+	return
+}
+
+// mergeFamilies moves relations of source family to target family.
+// Relations with the same sources are merged into one with renumbered children,
+// identical children are merged with their aliases, aliases of source family are added to target family
+// and references to source family by other names are rewritten to the name of target family
+func mergeFamilies(srcDoc *Doc, srcLine int, dstDoc *Doc, dstLine int) (changes map[Uri][]proto.TextEdit, err error) {
+	src := findFamilyByLine(srcDoc, srcLine)
+	dst := findFamilyByLine(dstDoc, dstLine)
+
+	if src == nil || dst == nil || src == dst {
+		return nil, fmt.Errorf("family not found")
+	}
+
+	changes = make(map[Uri][]proto.TextEdit)
+
+	add := func(uri Uri, edit proto.TextEdit) {
+		changes[uri] = append(changes[uri], edit)
+	}
+
+	// family names
+	aliases := TokensToStrings(dst.Aliases)
+	count := len(aliases)
+
+	for _, name := range TokensToStrings(src.Aliases) {
+		if name != dst.Name.Text && !slices.Contains(aliases, name) {
+			aliases = append(aliases, name)
+		}
+	}
+
+	if len(aliases) > count {
+		add(dstDoc.Uri, proto.TextEdit{
+			Range:   namesRange(dstDoc, dst.Name, dst.Aliases),
+			NewText: formatNames(dst.Name.Text, aliases),
+		})
+	}
+
+	names := slices.Concat([]string{dst.Name.Text}, aliases)
+
+	// edits of source family are applied to the text of moved relations
+	var movedEdits []proto.TextEdit
+
+	if family := findFamilyByNode(srcDoc.Uri, src); family != nil {
+		for ref, uri := range family.GetRefsIter() {
+			if slices.Contains(names, ref.Token.Text) {
+				continue
+			}
+
+			edit := proto.TextEdit{
+				Range:   TokenToRange(ref.Token),
+				NewText: dst.Name.Text,
+			}
+
+			if uri == srcDoc.Uri && src.Overlaps(ref.Token.Loc()) {
+				movedEdits = append(movedEdits, edit)
+			} else {
+				add(uri, edit)
+			}
+		}
+	}
+
+	// relations
+	relations := make(map[string]*fm.Relation)
+
+	for _, rel := range dst.Relations {
+		key := relationKey(rel)
+
+		if _, ok := relations[key]; !ok {
+			relations[key] = rel
+		}
+	}
+
+	var appendText strings.Builder
+
+	for _, rel := range src.Relations {
+		target, ok := relations[relationKey(rel)]
+
+		if ok && rel.Targets == nil {
+			continue
+		}
+
+		if !ok || target.Targets == nil || len(target.Targets.Persons) == 0 {
+			appendText.WriteString("\n\n")
+			appendText.WriteString(strings.TrimSpace(applyEdits(srcDoc, rel.Loc, editsOfLoc(movedEdits, rel.Loc))))
+			continue
+		}
+
+		persons := target.Targets.Persons
+		num := lastChildNum(persons)
+		last := persons[len(persons)-1]
+
+		var text strings.Builder
+
+		for _, p := range rel.Targets.Persons {
+			same := findSamePerson(persons, p)
+
+			if same != nil {
+				aliases := TokensToStrings(same.Aliases)
+				count := len(aliases)
+
+				for _, alias := range TokensToStrings(p.Aliases) {
+					if alias != same.Name.Text && !slices.Contains(aliases, alias) {
+						aliases = append(aliases, alias)
+					}
+				}
+
+				if len(aliases) > count {
+					add(dstDoc.Uri, proto.TextEdit{
+						Range:   namesRange(dstDoc, same.Name, same.Aliases),
+						NewText: formatNames(same.Name.Text, aliases),
+					})
+				}
+
+				continue
+			}
+
+			num++
+
+			child := strings.TrimSpace(applyEdits(srcDoc, personLoc(p), editsOfLoc(movedEdits, p.Loc)))
+
+			if last.Num != nil {
+				text.WriteString(fmt.Sprintf("\n%d. %s", num, child))
+			} else {
+				text.WriteString(", " + child)
+			}
+		}
+
+		if text.Len() > 0 {
+			pos := LocPosToPosition(last.End)
+
+			add(dstDoc.Uri, proto.TextEdit{
+				Range:   PositionToRange(pos),
+				NewText: text.String(),
+			})
+		}
+	}
+
+	if appendText.Len() > 0 {
+		add(dstDoc.Uri, proto.TextEdit{
+			Range:   PositionToRange(LocPosToPosition(dst.End)),
+			NewText: appendText.String(),
+		})
+	}
+
+	add(srcDoc.Uri, removeFamilyEdit(srcDoc, src))
+
+	return
+}
+
+// movePerson moves person from its family to target family.
+// Child is moved to the relation with the same sources in target family or to the new one,
+// with descendants are moved all relations of the family where person or its descendants are sources.
+// References which were resolved by context of the old family are rewritten to "Name Surname"
+func movePerson(doc *Doc, line int, name string, dstDoc *Doc, dstLine int, withDescendants bool) (changes map[Uri][]proto.TextEdit, err error) {
+	person := findPersonByName(doc, line, name)
+
+	if person == nil {
+		return nil, fmt.Errorf("person not found")
+	}
+
+	member := root.GetMemberByToken(doc.Uri, person.Name)
+	dst := findFamilyByLine(dstDoc, dstLine)
+
+	if member == nil || member.Family.Uri != doc.Uri || dst == nil || dst == member.Family.Node {
+		return nil, fmt.Errorf("family not found")
+	}
+
+	src := member.Family
+	child := member.Person
+
+	if !child.IsChild {
+		child = nil
+		withDescendants = true
+	}
+
+	relations := getMovedRelations(doc, member, withDescendants)
+
+	moved := map[*Member]bool{
+		member: true,
+	}
+
+	isMoved := func(p *fm.Person) bool {
+		return p == child || slices.Contains(relations, p.Relation)
+	}
+
+	for _, rel := range relations {
+		for p := range rel.PersonsIter() {
+			if mem := getPersonMember(doc, p); mem != nil && mem.Person == p {
+				moved[mem] = true
+			}
+		}
+	}
+
+	changes = make(map[Uri][]proto.TextEdit)
+
+	add := func(uri Uri, edit proto.TextEdit) {
+		changes[uri] = append(changes[uri], edit)
+	}
+
+	// edits of moved relations are applied to their text
+	var movedEdits []proto.TextEdit
+
+	addTokenEdit := func(uri Uri, p *fm.Person, edit proto.TextEdit) {
+		if uri == doc.Uri && isMoved(p) {
+			movedEdits = append(movedEdits, edit)
+		} else {
+			add(uri, edit)
+		}
+	}
+
+	// names of moved relations which are left in the old family
+	for _, rel := range relations {
+		for p := range rel.PersonsIter() {
+			if p.Name == nil || p.Surname != nil {
+				continue
+			}
+
+			mem := getPersonMember(doc, p)
+
+			if mem != nil && mem.Family == src && !moved[mem] {
+				addTokenEdit(doc.Uri, p, proto.TextEdit{
+					Range:   PositionToRange(namesRange(doc, p.Name, p.Aliases).End),
+					NewText: " " + src.Name,
+				})
+			}
+		}
+	}
+
+	// references to moved members
+	for mem := range moved {
+		for ref, refUri := range mem.GetRefsIter() {
+			p := ref.Person
+
+			if p == nil || p.Name == nil || p == child {
+				continue
+			}
+
+			switch {
+			case ref.Type == RefTypeName && refUri == doc.Uri && !isMoved(p):
+				addTokenEdit(refUri, p, proto.TextEdit{
+					Range:   PositionToRange(namesRange(doc, p.Name, p.Aliases).End),
+					NewText: " " + dst.Name.Text,
+				})
+
+			case ref.Type == RefTypeNameSurname || (ref.Type == RefTypeOrigin && ref.Member != mem):
+				if p.Surname != nil {
+					addTokenEdit(refUri, p, proto.TextEdit{
+						Range:   TokenToRange(p.Surname),
+						NewText: dst.Name.Text,
+					})
+				}
+			}
+		}
+	}
+
+	var appendText strings.Builder
+
+	// child
+	if child != nil {
+		rel := child.Relation
+
+		for _, edit := range removeTargetEdits(rel, child) {
+			add(doc.Uri, edit)
+		}
+
+		var key, sources []string
+
+		for _, p := range rel.Sources.Persons {
+			text := strings.TrimSpace(doc.GetTextByLoc(p.Loc))
+			mem := getPersonMember(doc, p)
+
+			if p.Name != nil && p.Surname == nil && mem != nil && mem.Family == src {
+				text = applyEdits(doc, p.Loc, []proto.TextEdit{{
+					Range:   PositionToRange(namesRange(doc, p.Name, p.Aliases).End),
+					NewText: " " + src.Name,
+				}})
+
+				key = append(key, p.Name.Text+" "+src.Name)
+			} else {
+				key = append(key, personKey(p))
+			}
+
+			sources = append(sources, strings.TrimSpace(text))
+		}
+
+		arrow := "="
+
+		if rel.Arrow != nil {
+			arrow = rel.Arrow.Text
+		}
+
+		relKey := strings.Join(key, " + ") + " " + arrow
+		index := slices.IndexFunc(dst.Relations, func(r *fm.Relation) bool {
+			return r.Targets != nil && len(r.Targets.Persons) > 0 && relationKey(r) == relKey
+		})
+
+		if index == -1 {
+			appendText.WriteString(fmt.Sprintf("\n\n%s %s\n1. %s", strings.Join(sources, " + "), arrow, personText(doc, child)))
+		} else {
+			persons := dst.Relations[index].Targets.Persons
+			last := persons[len(persons)-1]
+			text := ", " + personText(doc, child)
+
+			if last.Num != nil {
+				text = fmt.Sprintf("\n%d. %s", lastChildNum(persons)+1, personText(doc, child))
+			}
+
+			add(dstDoc.Uri, proto.TextEdit{
+				Range:   PositionToRange(LocPosToPosition(last.End)),
+				NewText: text,
+			})
+		}
+	}
+
+	// relations
+	for _, rel := range relations {
+		appendText.WriteString("\n\n")
+		appendText.WriteString(strings.TrimSpace(applyEdits(doc, rel.Loc, editsOfLoc(movedEdits, rel.Loc))))
+
+		add(doc.Uri, proto.TextEdit{
+			Range: Range{
+				Start: LocPosToPosition(prevRelationEnd(doc, rel)),
+				End:   LocPosToPosition(rel.End),
+			},
+			NewText: "",
+		})
+	}
+
+	if appendText.Len() > 0 {
+		add(dstDoc.Uri, proto.TextEdit{
+			Range:   PositionToRange(LocPosToPosition(dst.End)),
+			NewText: appendText.String(),
+		})
+	}
+
+	return
+}
+
+// getMovedRelations returns relations of member family where member or its descendants are sources
+func getMovedRelations(doc *Doc, member *Member, withDescendants bool) (list []*fm.Relation) {
+	if !withDescendants {
+		return
+	}
+
+	moved := map[*Member]bool{
+		member: true,
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, rel := range member.Family.Node.Relations {
+			if slices.Contains(list, rel) {
+				continue
+			}
+
+			found := slices.ContainsFunc(rel.Sources.Persons, func(p *fm.Person) bool {
+				return moved[getPersonMember(doc, p)]
+			})
+
+			if !found {
+				continue
+			}
+
+			list = append(list, rel)
+			changed = true
+
+			for p := range rel.PersonsIter() {
+				if mem := getPersonMember(doc, p); mem != nil && mem.Person == p {
+					moved[mem] = true
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(list, func(a, b *fm.Relation) int {
+		return slices.Index(member.Family.Node.Relations, a) - slices.Index(member.Family.Node.Relations, b)
+	})
+
+	return
+}
+
+// getMoveTargets returns all other families of the workspace,
+// families of the same file go first, then families of parents and partners of member, then the rest
+func getMoveTargets(member *Member) (list []*Family) {
+	family := member.Family
+	rank := make(map[*Family]int)
+
+	for f := range root.FamilyIter() {
+		if f == family {
+			continue
+		}
+
+		list = append(list, f)
+		rank[f] = 2
+
+		if f.Uri == family.Uri {
+			rank[f] = 0
+		}
+	}
+
+	origin := member.GetOrigin()
+
+	for _, relatives := range []iter.Seq[*Member]{root.ParentsIter(origin), root.PartnersIter(origin)} {
+		for mem := range relatives {
+			for _, f := range []*Family{mem.Family, mem.GetOrigin().Family} {
+				if rank[f] > 1 {
+					rank[f] = 1
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(list, func(a, b *Family) int {
+		return cmp.Or(
+			rank[a]-rank[b],
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Uri, b.Uri),
+		)
+	})
+
+	return
+}
+
+func getPersonMember(doc *Doc, p *fm.Person) *Member {
+	if p.Name == nil {
+		return nil
+	}
+
+	return root.GetMemberByToken(doc.Uri, p.Name)
+}
+
+// removeTargetEdits removes person from targets of relation and decreases numbers of next targets,
+// numbers are not changed when there is another target with the same number like "2a." and "2b."
+func removeTargetEdits(rel *fm.Relation, person *fm.Person) (edits []proto.TextEdit) {
+	persons := rel.Targets.Persons
+	index := slices.Index(persons, person)
+
+	if index == -1 {
+		return
+	}
+
+	if len(persons) == 1 {
+		return []proto.TextEdit{{
+			Range: Range{
+				Start: LocPosToPosition(rel.Sources.End),
+				End:   LocPosToPosition(rel.End),
+			},
+			NewText: "",
+		}}
+	}
+
+	r := Range{
+		Start: LocPosToPosition(person.Start),
+		End:   LocPosToPosition(persons[1].Start),
+	}
+
+	if index > 0 {
+		r = Range{
+			Start: LocPosToPosition(persons[index-1].End),
+			End:   LocPosToPosition(person.End),
+		}
+	}
+
+	edits = append(edits, proto.TextEdit{
+		Range:   r,
+		NewText: "",
+	})
+
+	if person.Num == nil {
+		return
+	}
+
+	removed, _ := parseChildNum(person.Num.Text)
+
+	for _, p := range persons {
+		if p == person || p.Num == nil {
+			continue
+		}
+
+		if num, _ := parseChildNum(p.Num.Text); num == removed {
+			return
+		}
+	}
+
+	for _, p := range persons[index+1:] {
+		if p.Num == nil {
+			continue
+		}
+
+		num, suffix := parseChildNum(p.Num.Text)
+
+		if num > removed {
+			edits = append(edits, proto.TextEdit{
+				Range:   TokenToRange(p.Num),
+				NewText: strconv.Itoa(num-1) + suffix + ".",
+			})
+		}
+	}
+
+	return
+}
+
+// prevRelationEnd returns end of previous relation or family header
+func prevRelationEnd(doc *Doc, rel *fm.Relation) fm.Position {
+	f := doc.FindFamilyByLoc(rel.Loc)
+	index := slices.Index(f.Relations, rel)
+
+	if index > 0 {
+		return f.Relations[index-1].End
+	}
+
+	return RangeToLoc(namesRange(doc, f.Name, f.Aliases)).End
+}
+
+// editsOfLoc returns edits inside of loc
+func editsOfLoc(edits []proto.TextEdit, loc fm.Loc) (list []proto.TextEdit) {
+	for _, edit := range edits {
+		if loc.Overlaps(RangeToLoc(edit.Range)) {
+			list = append(list, edit)
+		}
+	}
+
+	return
+}
+
+// applyEdits returns text of loc with applied edits
+func applyEdits(doc *Doc, loc fm.Loc, edits []proto.TextEdit) string {
+	slices.SortFunc(edits, func(a, b proto.TextEdit) int {
+		if a.Range.Start.Line != b.Range.Start.Line {
+			return int(a.Range.Start.Line) - int(b.Range.Start.Line)
+		}
+
+		return int(a.Range.Start.Character) - int(b.Range.Start.Character)
+	})
+
+	var s strings.Builder
+
+	start := loc.Start
+
+	for _, edit := range edits {
+		r := RangeToLoc(edit.Range)
+
+		s.WriteString(doc.GetTextByLoc(fm.Loc{Start: start, End: r.Start}))
+		s.WriteString(edit.NewText)
+
+		start = r.End
+	}
+
+	s.WriteString(doc.GetTextByLoc(fm.Loc{Start: start, End: loc.End}))
+
+	return s.String()
+}
+
+func findPersonByName(doc *Doc, line int, name string) *fm.Person {
+	for _, f := range doc.Root.Families {
+		for _, rel := range f.Relations {
+			for p := range rel.PersonsIter() {
+				if p.Name != nil && p.Name.Line == line && p.Name.Text == name {
+					return p
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func findFamilyByNode(uri Uri, node *fm.Family) *Family {
+	for f := range root.FamiliesByUriIter(uri) {
+		if f.Node == node {
+			return f
+		}
+	}
+
+	return nil
+}
+
+// getFamilyDuplicates returns other families which have the same name or alias as family node
+func getFamilyDuplicates(uri Uri, node *fm.Family) iter.Seq[*Family] {
+	return func(yield func(*Family) bool) {
+		family := findFamilyByNode(uri, node)
+
+		if family == nil {
+			return
+		}
+
+		uniq := make(map[*Family]bool)
+		uniq[family] = true
+
+		check := func(f *Family) bool {
+			if f == nil || uniq[f] {
+				return true
+			}
+
+			uniq[f] = true
+
+			return yield(f)
+		}
+
+		for name := range family.NamesIter() {
+			dups, ok := root.Duplicates[name]
+
+			if !ok {
+				continue
+			}
+
+			if !check(root.Families[name]) {
+				return
+			}
+
+			for _, dup := range dups {
+				if !check(dup.Family) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// removeFamilyEdit removes family with empty lines around it
+func removeFamilyEdit(doc *Doc, f *fm.Family) proto.TextEdit {
+	families := doc.Root.Families
+	index := slices.Index(families, f)
+
+	start := f.Start
+	end := f.End
+
+	if index+1 < len(families) {
+		end = families[index+1].Start
+	} else if index > 0 {
+		start = families[index-1].End
+	}
+
+	return proto.TextEdit{
+		Range: Range{
+			Start: LocPosToPosition(start),
+			End:   LocPosToPosition(end),
+		},
+		NewText: "",
+	}
+}
+
+// namesRange returns range of name with aliases in brackets
+func namesRange(doc *Doc, name *fm.Token, aliases []*fm.Token) Range {
+	r := TokenToRange(name)
+	count := len(aliases)
+
+	if count == 0 {
+		return r
+	}
+
+	last := aliases[count-1]
+	r.End = TokenEndToPosition(last)
+
+	_, next := doc.PrevNextNonSpaceTokens(last)
+
+	if next != nil && next.SubType == fm.TokenBracketRight && next.Line == last.Line {
+		r.End = TokenEndToPosition(next)
+	}
+
+	return r
+}
+
+func formatNames(name string, aliases []string) string {
+	if len(aliases) == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(aliases, ", "))
+}
+
+// relationKey returns sources of relation as text without aliases and extra spaces
+func relationKey(rel *fm.Relation) string {
+	names := make([]string, len(rel.Sources.Persons))
+
+	for i, p := range rel.Sources.Persons {
+		names[i] = personKey(p)
+	}
+
+	key := strings.Join(names, " + ")
+
+	if rel.Arrow != nil {
+		key += " " + rel.Arrow.Text
+	}
+
+	return key
+}
+
+func personKey(p *fm.Person) string {
+	if p.Unknown != nil || p.Name == nil {
+		return "?"
+	}
+
+	if p.Surname != nil {
+		return p.Name.Text + " " + p.Surname.Text
+	}
+
+	return p.Name.Text
+}
+
+func findSamePerson(list []*fm.Person, person *fm.Person) *fm.Person {
+	if person.Name == nil {
+		return nil
+	}
+
+	key := personKey(person)
+
+	for _, p := range list {
+		if p.Name != nil && personKey(p) == key {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// personText returns text of person without child number
+func personText(doc *Doc, p *fm.Person) string {
+	return strings.TrimSpace(doc.GetTextByLoc(personLoc(p)))
+}
+
+// personLoc returns loc of person without child number
+func personLoc(p *fm.Person) fm.Loc {
+	loc := p.Loc
+
+	if p.Num != nil {
+		loc.Start = fm.Position{
+			Line: p.Num.Line,
+			Char: p.Num.EndChar(),
+		}
+	}
+
+	return loc
+}
+
+// lastChildNum returns the highest number of children, numbers could have gaps and letters like "3a."
+func lastChildNum(persons []*fm.Person) (res int) {
+	for _, p := range persons {
+		if p.Num == nil {
+			res++
+			continue
+		}
+
+		num, _ := parseChildNum(p.Num.Text)
+		res = max(res, num)
+	}
+
+	return
+}
+
+// parseChildNum returns number and letters of child number like "3a."
+func parseChildNum(text string) (num int, suffix string) {
+	text = strings.TrimSuffix(text, ".")
+	digits := strings.TrimRightFunc(text, unicode.IsLetter)
+	num, _ = strconv.Atoi(digits)
+
+	return num, text[len(digits):]
+}
+
+func findFamilyByHeader(doc *Doc, r Range) *fm.Family {
+	return findFamilyByLine(doc, int(r.Start.Line))
+}
+
+func findFamilyByLine(doc *Doc, line int) *fm.Family {
+	for _, f := range doc.Root.Families {
+		if f.Name.Line == line {
+			return f
+		}
+	}
+
+	return nil
+}
+
+func isKindAllowed(only []proto.CodeActionKind, kind proto.CodeActionKind) bool {
+	if len(only) == 0 {
+		return true
+	}
+
+	for _, item := range only {
+		if kind == item || strings.HasPrefix(kind, item+".") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,446 @@
+package providers
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestExtractFamily(t *testing.T) {
+	list := []struct {
+		name   string
+		files  map[string]string
+		line   int
+		expect map[string]string
+	}{
+		{
+			name: "last family to new file",
+			files: map[string]string{
+				"Family.fml": "Potter\n\nJames + Lily =\n1. Harry\n\nWeasley\n\nArthur + Molly =\n1. Ron\n2. Ginny\n",
+			},
+			line: 5,
+			expect: map[string]string{
+				"Family.fml":  "Potter\n\nJames + Lily =\n1. Harry\n",
+				"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ron\n2. Ginny\n",
+			},
+		},
+		{
+			name: "first family to new file",
+			files: map[string]string{
+				"Family.fml": "Potter\n\nJames + Lily =\n1. Harry\n\nWeasley\n\nArthur + Molly =\n1. Ron\n",
+			},
+			line: 0,
+			expect: map[string]string{
+				"Family.fml": "Weasley\n\nArthur + Molly =\n1. Ron\n",
+				"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n",
+			},
+		},
+		{
+			name: "family to existing file",
+			files: map[string]string{
+				"Family.fml":  "Potter\n\nJames + Lily =\n1. Harry\n\nWeasley\n\nArthur + Molly =\n1. Ron\n",
+				"Weasley.fml": "Weasley\n\nBill + Fleur =\n1. Victoire",
+			},
+			line: 5,
+			expect: map[string]string{
+				"Family.fml":  "Potter\n\nJames + Lily =\n1. Harry\n",
+				"Weasley.fml": "Weasley\n\nBill + Fleur =\n1. Victoire\n\nWeasley\n\nArthur + Molly =\n1. Ron\n",
+			},
+		},
+	}
+
+	for _, item := range list {
+		folder := testFolder(t, item.files)
+
+		changes, err := extractFamily(GetDoc(folder+"/Family.fml"), item.line)
+
+		if err != nil {
+			t.Errorf("%s: %v", item.name, err)
+			continue
+		}
+
+		edits := make(map[Uri][]proto.TextEdit)
+
+		for _, change := range changes {
+			if edit, ok := change.(proto.TextDocumentEdit); ok {
+				for _, e := range edit.Edits {
+					edits[edit.TextDocument.URI] = append(edits[edit.TextDocument.URI], e.(proto.TextEdit))
+				}
+			}
+		}
+
+		checkFiles(t, item.name, folder, item.files, edits, item.expect)
+	}
+}
+
+func TestMergeFamilies(t *testing.T) {
+	list := []struct {
+		name    string
+		files   map[string]string
+		src     string
+		srcLine int
+		dst     string
+		dstLine int
+		expect  map[string]string
+	}{
+		{
+			name: "same file",
+			files: map[string]string{
+				"Smith.fml": "Smith\n\nJohn + Mary =\n1. Anna\n2. Bob\n\nSmith (Smyth)\n\nJohn + Mary =\n1. Bob (Robert)\n2. Carl\n\nCarl + ? =\n1. Dan\n",
+			},
+			src:     "Smith.fml",
+			srcLine: 6,
+			dst:     "Smith.fml",
+			dstLine: 0,
+			expect: map[string]string{
+				"Smith.fml": "Smith (Smyth)\n\nJohn + Mary =\n1. Anna\n2. Bob (Robert)\n3. Carl\n\nCarl + ? =\n1. Dan\n",
+			},
+		},
+		{
+			name: "numbers with gaps and letters",
+			files: map[string]string{
+				"Smith.fml": "Smith\n\nJohn + Mary =\n1. Anna\n3a. Bob\n\nSmith\n\nJohn + Mary =\n1. Carl\n",
+			},
+			src:     "Smith.fml",
+			srcLine: 6,
+			dst:     "Smith.fml",
+			dstLine: 0,
+			expect: map[string]string{
+				"Smith.fml": "Smith\n\nJohn + Mary =\n1. Anna\n3a. Bob\n4. Carl\n",
+			},
+		},
+		{
+			name: "references in other files",
+			files: map[string]string{
+				"Smith.fml": "Smith\n\nJohn + Mary =\n1. Anna\n",
+				"Smyth.fml": "Smyth (Smith)\n\nJohn + Mary =\n1. Bob\n\nBob + Kate =\n1. Eve\n",
+				"Other.fml": "Other\n\nBob Smyth + Jane =\n1. Tom\n\nAnna Smith + Sam =\n1. Liz\n",
+			},
+			src:     "Smyth.fml",
+			srcLine: 0,
+			dst:     "Smith.fml",
+			dstLine: 0,
+			expect: map[string]string{
+				"Smith.fml": "Smith\n\nJohn + Mary =\n1. Anna\n2. Bob\n\nBob + Kate =\n1. Eve\n",
+				"Smyth.fml": "\n",
+				"Other.fml": "Other\n\nBob Smith + Jane =\n1. Tom\n\nAnna Smith + Sam =\n1. Liz\n",
+			},
+		},
+	}
+
+	for _, item := range list {
+		folder := testFolder(t, item.files)
+
+		edits, err := mergeFamilies(GetDoc(folder+"/"+item.src), item.srcLine, GetDoc(folder+"/"+item.dst), item.dstLine)
+
+		if err != nil {
+			t.Errorf("%s: %v", item.name, err)
+			continue
+		}
+
+		checkFiles(t, item.name, folder, item.files, edits, item.expect)
+	}
+}
+
+func TestRelationKey(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Smith.fml": "Smith\n\nJohn (Jack)  +  Mary Brown =\n1. Anna\n\n? + Anna\n",
+	})
+
+	doc := GetDoc(folder + "/Smith.fml")
+	keys := []string{"John + Mary Brown =", "? + Anna"}
+
+	for i, rel := range doc.Root.Families[0].Relations {
+		if key := relationKey(rel); key != keys[i] {
+			t.Errorf("%q != %q", key, keys[i])
+		}
+	}
+}
+
+func TestMovePerson(t *testing.T) {
+	list := []struct {
+		name            string
+		files           map[string]string
+		line            int
+		person          string
+		dst             string
+		withDescendants bool
+		expect          map[string]string
+	}{
+		{
+			name: "child to new relation",
+			files: map[string]string{
+				"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3. Tom\n",
+				"Evans.fml":  "Evans\n\n? + ? =\n1. Lily\n",
+			},
+			line:   4,
+			person: "Rose",
+			dst:    "Evans.fml",
+			expect: map[string]string{
+				"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Tom\n",
+				"Evans.fml":  "Evans\n\n? + ? =\n1. Lily\n\nJames Potter + Lily Potter =\n1. Rose\n",
+			},
+		},
+		{
+			name: "child to unrelated family in another file",
+			files: map[string]string{
+				"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n",
+				"Malfoy.fml": "Malfoy\n\nLucius + Narcissa =\n1. Draco\n",
+			},
+			line:   4,
+			person: "Rose",
+			dst:    "Malfoy.fml",
+			expect: map[string]string{
+				"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n",
+				"Malfoy.fml": "Malfoy\n\nLucius + Narcissa =\n1. Draco\n\nJames Potter + Lily Potter =\n1. Rose\n",
+			},
+		},
+		{
+			name: "child with references in old family",
+			files: map[string]string{
+				"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n\nRose + Tom =\n1. Ann\n",
+				"Weasley.fml": "Weasley\n\nJames Potter + Lily Potter =\n1. Ron\n3. Ginny\n",
+			},
+			line:   4,
+			person: "Rose",
+			dst:    "Weasley.fml",
+			expect: map[string]string{
+				"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n\nRose Weasley + Tom =\n1. Ann\n",
+				"Weasley.fml": "Weasley\n\nJames Potter + Lily Potter =\n1. Ron\n3. Ginny\n4. Rose\n",
+			},
+		},
+		{
+			name: "child with descendants",
+			files: map[string]string{
+				"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n\nRose + Tom =\n1. Ann\n\nHarry + Ginny Weasley =\n1. Albus\n",
+				"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ginny\n",
+			},
+			line:            4,
+			person:          "Rose",
+			dst:             "Weasley.fml",
+			withDescendants: true,
+			expect: map[string]string{
+				"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny Weasley =\n1. Albus\n",
+				"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ginny\n\nJames Potter + Lily Potter =\n1. Rose\n\nRose + Tom =\n1. Ann\n",
+			},
+		},
+	}
+
+	for _, item := range list {
+		folder := testFolder(t, item.files)
+
+		edits, err := movePerson(GetDoc(folder+"/Potter.fml"), item.line, item.person, GetDoc(folder+"/"+item.dst), 0, item.withDescendants)
+
+		if err != nil {
+			t.Errorf("%s: %v", item.name, err)
+			continue
+		}
+
+		checkFiles(t, item.name, folder, item.files, edits, item.expect)
+	}
+}
+
+func TestGetMoveTargets(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Lily Evans =\n1. Harry\n\nHarry + Ginny Weasley =\n1. Albus\n\nDursley\n\nVernon + Petunia Evans\n",
+		"Evans.fml":   "Evans\n\n? + ? =\n1. Lily\n2. Petunia\n",
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ginny\n",
+		"Malfoy.fml":  "Malfoy\n\nLucius + Narcissa =\n1. Draco\n",
+	})
+
+	doc := GetDoc(folder + "/Potter.fml")
+	mem := root.GetMemberByToken(doc.Uri, doc.Root.Families[0].Relations[0].Targets.Persons[0].Name)
+
+	var names []string
+
+	for _, f := range getMoveTargets(mem) {
+		names = append(names, f.Name)
+	}
+
+	if expect := []string{"Dursley", "Evans", "Weasley", "Malfoy"}; !slices.Equal(names, expect) {
+		t.Errorf("%v != %v", names, expect)
+	}
+}
+
+func TestRemoveTargetEdits(t *testing.T) {
+	list := []struct {
+		text   string
+		index  int
+		expect string
+	}{
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3. Tom\n",
+			index:  0,
+			expect: "Potter\n\nJames + Lily =\n1. Rose\n2. Tom\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n3. Rose\n5. Tom\n",
+			index:  1,
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n4. Tom\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n2a. Rose\n2b. Tom\n3. Ann\n",
+			index:  1,
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2b. Tom\n3. Ann\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3a. Tom\n",
+			index:  1,
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2a. Tom\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n",
+			index:  0,
+			expect: "Potter\n\nJames + Lily\n",
+		},
+	}
+
+	for _, item := range list {
+		folder := testFolder(t, map[string]string{"Potter.fml": item.text})
+		rel := GetDoc(folder + "/Potter.fml").Root.Families[0].Relations[0]
+
+		res := applyTextEdits(item.text, removeTargetEdits(rel, rel.Targets.Persons[item.index]))
+
+		if res != item.expect {
+			t.Errorf("%q != %q", res, item.expect)
+		}
+	}
+}
+
+func TestApplyEdits(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny =\n1. Albus\n",
+	})
+
+	doc := GetDoc(folder + "/Potter.fml")
+	rel := doc.Root.Families[0].Relations[1]
+
+	res := applyEdits(doc, rel.Loc, []proto.TextEdit{
+		{
+			Range:   PositionToRange(Position{Line: 5, Character: 13}),
+			NewText: " Weasley",
+		},
+		{
+			Range:   PositionToRange(Position{Line: 5, Character: 5}),
+			NewText: " Potter",
+		},
+		{
+			Range: Range{
+				Start: Position{Line: 6, Character: 3},
+				End:   Position{Line: 6, Character: 8},
+			},
+			NewText: "Lily",
+		},
+	})
+
+	if expect := "Harry Potter + Ginny Weasley =\n1. Lily"; res != expect {
+		t.Errorf("%q != %q", res, expect)
+	}
+}
+
+// testFolder creates root of temporary folder with files by their relative paths
+func testFolder(t *testing.T, files map[string]string) Uri {
+	dir := writeTestFiles(t, files)
+
+	root = CreateRoot()
+	folder := ToUri(dir)
+
+	err := root.SetFolders([]Uri{folder})
+
+	if err == nil {
+		err = root.UpdateDirty()
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return folder
+}
+
+// checkFiles applies edits to files and compares result with expected files
+func checkFiles(t *testing.T, name string, folder Uri, files map[string]string, edits map[Uri][]proto.TextEdit, expect map[string]string) {
+	for uri := range edits {
+		file := strings.TrimPrefix(uri, folder+"/")
+
+		if _, ok := expect[file]; !ok {
+			t.Errorf("%s: unexpected edits of %s", name, file)
+		}
+	}
+
+	for file, text := range expect {
+		res := applyTextEdits(files[file], edits[folder+"/"+file])
+
+		if res != text {
+			t.Errorf("%s: %s\n%q\n!=\n%q", name, file, res, text)
+		}
+	}
+}
+
+// writeTestFiles writes files to a new temp folder and returns its path
+func writeTestFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+
+	for name, text := range files {
+		path := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+
+		if err == nil {
+			err = os.WriteFile(path, []byte(text), 0644)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// applyTextEdits returns text with applied edits, edits at the same position are inserted in their order
+func applyTextEdits(text string, edits []proto.TextEdit) string {
+	lines := strings.SplitAfter(text, "\n")
+
+	offset := func(pos Position) int {
+		res := 0
+
+		for i := 0; i < int(pos.Line) && i < len(lines); i++ {
+			res += len(lines[i])
+		}
+
+		if int(pos.Line) < len(lines) {
+			res += len(string([]rune(lines[pos.Line])[:pos.Character]))
+		}
+
+		return res
+	}
+
+	indexes := make([]int, len(edits))
+
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	slices.SortFunc(indexes, func(a, b int) int {
+		if diff := offset(edits[b].Range.Start) - offset(edits[a].Range.Start); diff != 0 {
+			return diff
+		}
+
+		return b - a
+	})
+
+	for _, i := range indexes {
+		edit := edits[i]
+		text = text[:offset(edit.Range.Start)] + edit.NewText + text[offset(edit.Range.End):]
+	}
+
+	return text
+}
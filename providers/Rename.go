@@ -1,7 +1,13 @@
 package providers
 
 import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
 	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
 	. "github.com/redexp/familymarkup-lsp/utils"
 	proto "github.com/tliron/glsp/protocol_3_16"
 )
@@ -55,6 +61,10 @@ func Rename(_ *Ctx, params *proto.RenameParams) (res *proto.WorkspaceEdit, err e
 		if IsUriName(uri, f.Name) {
 			newUri, err := RenameUri(uri, params.NewName)
 
+			if err == nil && newUri != uri && uriExists(newUri) {
+				err = fmt.Errorf("file %s already exists", newUri)
+			}
+
 			if err != nil {
 				return nil, err
 			}
@@ -66,6 +76,24 @@ func Rename(_ *Ctx, params *proto.RenameParams) (res *proto.WorkspaceEdit, err e
 			})
 		}
 
+		for _, folder := range getFamilyInfoFolders(f) {
+			newFolder, err := RenameUri(folder, params.NewName)
+
+			if err == nil && newFolder != folder && uriExists(newFolder) {
+				err = fmt.Errorf("folder %s already exists", newFolder)
+			}
+
+			if err != nil {
+				return nil, err
+			}
+
+			edits = append(edits, proto.RenameFile{
+				Kind:   "rename",
+				OldURI: folder,
+				NewURI: newFolder,
+			})
+		}
+
 		res.DocumentChanges = edits
 
 		return
@@ -94,3 +122,52 @@ func Rename(_ *Ctx, params *proto.RenameParams) (res *proto.WorkspaceEdit, err e
 
 	return
 }
+
+// getFamilyInfoFolders returns folders named by family surname which contain Markdown files of its members
+func getFamilyInfoFolders(f *Family) (list []Uri) {
+	prefix := strings.TrimSuffix(root.InfoFolder(f.Uri), "/") + "/"
+
+	add := func(folder Uri) {
+		if !slices.Contains(list, folder) {
+			list = append(list, folder)
+		}
+	}
+
+	for mem := range f.MembersIter() {
+		if !strings.HasPrefix(mem.InfoUri, prefix) {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(mem.InfoUri, prefix), "/")
+		index := slices.Index(parts[:len(parts)-1], f.Name)
+
+		if index != -1 {
+			add(prefix + strings.Join(parts[:index+1], "/"))
+		}
+	}
+
+	path, err := UriToPath(prefix + f.Name)
+
+	if err != nil {
+		return
+	}
+
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		add(prefix + f.Name)
+	}
+
+	return
+}
+
+// uriExists prevents renaming of file or folder over existing one, client would silently skip or overwrite it
+func uriExists(uri Uri) bool {
+	path, err := UriToPath(uri)
+
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+
+	return err == nil
+}
@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"testing"
+
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestRenameFamilyFolder(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml":      "Potter\n\nJames + Lily =\n1. Harry\n",
+		"Potter/Harry.md": "# Harry\n",
+		"Evans/Lily.md":   "# Lily\n",
+	})
+
+	rename := func(name string) (*proto.WorkspaceEdit, error) {
+		return Rename(nil, &proto.RenameParams{
+			TextDocumentPositionParams: proto.TextDocumentPositionParams{
+				TextDocument: proto.TextDocumentIdentifier{URI: folder + "/Potter.fml"},
+				Position:     proto.Position{Line: 0, Character: 1},
+			},
+			NewName: name,
+		})
+	}
+
+	if _, err := rename("Evans"); err == nil {
+		t.Errorf("expected error on existing folder")
+	}
+
+	res, err := rename("Dursley")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := false
+
+	for _, edit := range res.DocumentChanges {
+		if r, ok := edit.(proto.RenameFile); ok && r.NewURI == folder+"/Dursley" {
+			found = r.OldURI == folder+"/Potter"
+		}
+	}
+
+	if !found {
+		t.Errorf("folder rename not found in %v", res.DocumentChanges)
+	}
+}
@@ -0,0 +1,105 @@
+package providers
+
+import (
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	fm "github.com/redexp/familymarkup-parser"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func SelectionRange(_ *Ctx, params *proto.SelectionRangeParams) (res []proto.SelectionRange, err error) {
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	doc := GetDoc(params.TextDocument.URI)
+
+	if doc == nil {
+		return
+	}
+
+	res = make([]proto.SelectionRange, len(params.Positions))
+
+	for i, pos := range params.Positions {
+		var parent *proto.SelectionRange
+
+		for _, loc := range getSelectionLocs(doc, pos) {
+			r := LocToRange(loc)
+
+			if parent != nil && parent.Range == r {
+				continue
+			}
+
+			parent = &proto.SelectionRange{
+				Range:  r,
+				Parent: parent,
+			}
+		}
+
+		if parent == nil {
+			parent = &proto.SelectionRange{
+				Range: PositionToRange(pos),
+			}
+		}
+
+		res[i] = *parent
+	}
+
+	return
+}
+
+// getSelectionLocs returns locations of nodes around position from document to token
+func getSelectionLocs(doc *Doc, pos Position) (list []fm.Loc) {
+	list = append(list, doc.Root.Loc)
+
+	r := PositionToRange(pos)
+	loc := RangeToLoc(r)
+
+	f := doc.FindFamilyByRange(r)
+
+	if f != nil {
+		list = append(list, f.Loc)
+
+		header := namesRange(doc, f.Name, f.Aliases)
+
+		if RangeToLoc(header).Overlaps(loc) {
+			list = append(list, RangeToLoc(header))
+		}
+	}
+
+	rel := doc.FindRelationByRange(r)
+
+	if rel != nil {
+		list = append(list, rel.Loc)
+
+		for _, relList := range []*fm.RelList{rel.Sources, rel.Targets} {
+			if relList != nil && relList.Overlaps(loc) {
+				list = append(list, relList.Loc)
+			}
+		}
+	}
+
+	p := doc.FindPersonByRange(r)
+
+	if p != nil {
+		list = append(list, p.Loc)
+
+		if p.Num != nil && p.Name != nil {
+			list = append(list, fm.Loc{
+				Start: p.Name.Loc().Start,
+				End:   p.End,
+			})
+		}
+	}
+
+	token := doc.GetTokenByPosition(pos)
+
+	if token != nil && token.Type&(fm.TokenSpace|fm.TokenNewLine|fm.TokenEmptyLine) == 0 {
+		list = append(list, token.Loc())
+	}
+
+	return
+}
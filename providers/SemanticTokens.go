@@ -8,7 +8,9 @@ import (
 
 	fm "github.com/redexp/familymarkup-parser"
 
+	. "github.com/redexp/familymarkup-lsp/state"
 	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
 	proto "github.com/tliron/glsp/protocol_3_16"
 )
 
@@ -37,10 +39,18 @@ var Legend = CreateLegend([]string{
 	"operator.sources.join",
 	"operator.arrow",
 	"string.label",
-})
+}, ModDuplicate, ModUnresolved, ModDocumented, ModOrigin)
+
+// additional modifiers of names and surnames
+const (
+	ModDuplicate  = "duplicate"  // name or surname is declared more than once
+	ModUnresolved = "unresolved" // unknown person or family
+	ModDocumented = "documented" // person has Markdown file
+	ModOrigin     = "origin"     // person came to the family from another one
+)
 
 func SemanticTokensFull(_ *Ctx, params *proto.SemanticTokensParams) (res *proto.SemanticTokens, err error) {
-	tokens, uri, err := getSemanticTokens(params.TextDocument.URI)
+	tokens, uri, err := getSemanticTokens(params.TextDocument.URI, nil)
 
 	if err != nil {
 		return
@@ -56,7 +66,7 @@ func SemanticTokensFull(_ *Ctx, params *proto.SemanticTokensParams) (res *proto.
 }
 
 func SemanticTokensDelta(_ *Ctx, params *proto.SemanticTokensDeltaParams) (res any, err error) {
-	tokens, uri, err := getSemanticTokens(params.TextDocument.URI)
+	tokens, uri, err := getSemanticTokens(params.TextDocument.URI, nil)
 
 	if err != nil {
 		return
@@ -85,7 +95,21 @@ func SemanticTokensDelta(_ *Ctx, params *proto.SemanticTokensDeltaParams) (res a
 	return
 }
 
-func CreateLegend(list []string) (legend *LegendType) {
+func SemanticTokensRange(_ *Ctx, params *proto.SemanticTokensRangeParams) (res any, err error) {
+	tokens, _, err := getSemanticTokens(params.TextDocument.URI, &params.Range)
+
+	if err != nil {
+		return
+	}
+
+	res = proto.SemanticTokens{
+		Data: tokens,
+	}
+
+	return
+}
+
+func CreateLegend(list []string, extraModifiers ...string) (legend *LegendType) {
 	types := make([]string, 0)
 	modifiers := make([]string, 0)
 
@@ -109,7 +133,7 @@ func CreateLegend(list []string) (legend *LegendType) {
 				modifiers = append(modifiers, m)
 			}
 
-			modMask = modMask | slices.Index(modifiers, m)
+			modMask = modMask | 1<<slices.Index(modifiers, m)
 		}
 
 		legend.Map[name] = Tokens{
@@ -118,6 +142,12 @@ func CreateLegend(list []string) (legend *LegendType) {
 		}
 	}
 
+	for _, m := range extraModifiers {
+		if !slices.Contains(modifiers, m) {
+			modifiers = append(modifiers, m)
+		}
+	}
+
 	legend.Types = types
 	legend.Modifiers = modifiers
 
@@ -134,7 +164,21 @@ func (legend *LegendType) Get(name string) (proto.UInteger, proto.UInteger) {
 	return list[0], list[1]
 }
 
-func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
+func (legend *LegendType) GetModifiers(names ...string) (mask proto.UInteger) {
+	for _, name := range names {
+		index := slices.Index(legend.Modifiers, name)
+
+		if index == -1 {
+			panic("unknown modifier: " + name)
+		}
+
+		mask |= 1 << index
+	}
+
+	return
+}
+
+func getSemanticTokens(docUri string, r *Range) (result Tokens, uri string, err error) {
 	uri = NormalizeUri(docUri)
 
 	err = root.UpdateDirty()
@@ -150,20 +194,34 @@ func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
 	}
 
 	type Item struct {
-		token *fm.Token
-		key   string
+		token     *fm.Token
+		key       string
+		modifiers []string
 	}
 
 	var list []Item
 
+	skip := func(token *fm.Token) bool {
+		return token == nil || (r != nil && !RangeToLoc(*r).Overlaps(token.Loc()))
+	}
+
 	add := func(token *fm.Token, key string) {
-		if token == nil {
+		if skip(token) {
 			return
 		}
 
 		list = append(list, Item{token: token, key: key})
 	}
 
+	// modifiers are resolved only for tokens in range
+	addMod := func(token *fm.Token, key string, getModifiers func(Uri, *fm.Token) []string) {
+		if skip(token) {
+			return
+		}
+
+		list = append(list, Item{token: token, key: key, modifiers: getModifiers(uri, token)})
+	}
+
 	addArr := func(tokens []*fm.Token, key string) {
 		for _, token := range tokens {
 			add(token, key)
@@ -174,8 +232,11 @@ func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
 
 	for _, f := range doc.Root.Families {
 		addArr(f.Comments, "comment")
-		add(f.Name, "class.declaration.family_name")
-		addArr(f.Aliases, "class.declaration.family_name.alias")
+		addMod(f.Name, "class.declaration.family_name", getFamilyModifiers)
+
+		for _, alias := range f.Aliases {
+			addMod(alias, "class.declaration.family_name.alias", getFamilyModifiers)
+		}
 
 		for _, rel := range f.Relations {
 			addArr(rel.Comments, "comment")
@@ -202,9 +263,9 @@ func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
 						key = "property.declaration.static.name.def"
 					}
 
-					add(p.Name, key)
+					addMod(p.Name, key, getNameModifiers)
 					addArr(p.Aliases, "property.declaration.static.name.def.alias")
-					add(p.Surname, "class.family_name.ref")
+					addMod(p.Surname, "class.family_name.ref", getSurnameModifiers)
 				}
 
 				key := "punctuation.delimiter.sources"
@@ -247,6 +308,7 @@ func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
 		}
 
 		t, m := Legend.Get(item.key)
+		m |= Legend.GetModifiers(item.modifiers...)
 
 		result[i*5] = proto.UInteger(deltaLine)
 		result[i*5+1] = proto.UInteger(deltaStartChar)
@@ -260,6 +322,48 @@ func getSemanticTokens(docUri string) (result Tokens, uri string, err error) {
 	return
 }
 
+func getFamilyModifiers(_ Uri, token *fm.Token) (list []string) {
+	if _, ok := root.Duplicates[token.Text]; ok {
+		list = append(list, ModDuplicate)
+	}
+
+	return
+}
+
+func getSurnameModifiers(uri Uri, token *fm.Token) (list []string) {
+	ref := root.GetRefByToken(uri, token)
+
+	if ref == nil || ref.Family == nil {
+		return []string{ModUnresolved}
+	}
+
+	return getFamilyModifiers(uri, token)
+}
+
+func getNameModifiers(uri Uri, token *fm.Token) (list []string) {
+	ref := root.GetRefByToken(uri, token)
+
+	if ref == nil || ref.Member == nil {
+		return []string{ModUnresolved}
+	}
+
+	mem := ref.Member
+
+	if ref.Type == RefTypeOrigin {
+		list = append(list, ModOrigin)
+	}
+
+	if _, ok := mem.Family.Duplicates[mem.Name]; ok {
+		list = append(list, ModDuplicate)
+	}
+
+	if mem.InfoUri != "" || (mem.Origin != nil && mem.Origin.InfoUri != "") {
+		list = append(list, ModDocumented)
+	}
+
+	return
+}
+
 func deltaSemanticTokens(prevTokens, tokens Tokens) (st, delCount uint32, insert Tokens) {
 	prevLen := uint32(len(prevTokens))
 	curLen := uint32(len(tokens))
@@ -3,6 +3,8 @@ package providers
 import (
 	"slices"
 	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/types"
 )
 
 func TestTokensDelta(t *testing.T) {
@@ -108,3 +110,51 @@ func TestTokensDelta(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateLegend(t *testing.T) {
+	legend := CreateLegend([]string{"class.a", "property.b.c"}, "d", "b")
+
+	if !slices.Equal(legend.Types, []string{"class", "property"}) || !slices.Equal(legend.Modifiers, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("legend: %v %v", legend.Types, legend.Modifiers)
+	}
+
+	if tp, mod := legend.Get("property.b.c"); tp != 1 || mod != 0b110 {
+		t.Errorf("property.b.c: %d %b", tp, mod)
+	}
+
+	if mod := legend.GetModifiers("a", "d"); mod != 0b1001 {
+		t.Errorf("modifiers: %b", mod)
+	}
+}
+
+func TestSemanticTokensWithoutSurname(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Rose Weasley\n",
+	})
+
+	tokens, _, err := getSemanticTokens(folder+"/Potter.fml", nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tokens) == 0 || len(tokens)%5 != 0 {
+		t.Fatalf("tokens: %v", tokens)
+	}
+
+	tokens, _, err = getSemanticTokens(folder+"/Potter.fml", &Range{
+		Start: Position{Line: 3, Character: 0},
+		End:   Position{Line: 4, Character: 0},
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	num, _ := Legend.Get("number.targets")
+	name, _ := Legend.Get("property.declaration.static.name.def")
+
+	if len(tokens) != 10 || tokens[3] != num || tokens[8] != name {
+		t.Errorf("range tokens: %v", tokens)
+	}
+}
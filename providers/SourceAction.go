@@ -0,0 +1,249 @@
+package providers
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	fm "github.com/redexp/familymarkup-parser"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+// types of source actions
+const (
+	RenumberChildrenSource = uint8(iota + 160)
+	SortChildrenByNameSource
+	SortChildrenByBirthSource
+)
+
+const (
+	CodeActionKindSourceRenumber = proto.CodeActionKind("source.renumber")
+	CodeActionKindSourceSort     = proto.CodeActionKind("source.sort")
+)
+
+// getSourceActions offers actions which are cheap to check, sorting by birth dates reads Markdown files,
+// so it is offered for any relation with children and its edits are computed on resolve
+func getSourceActions(uri Uri, params *proto.CodeActionParams) (list []proto.CodeAction) {
+	doc := GetDoc(uri)
+
+	if doc == nil {
+		return
+	}
+
+	rel := doc.FindRelationByRange(params.Range)
+
+	if rel == nil || rel.Targets == nil || len(rel.Targets.Persons) < 2 {
+		return
+	}
+
+	only := params.Context.Only
+
+	add := func(kind proto.CodeActionKind, title string, t uint8) {
+		if !isKindAllowed(only, kind) {
+			return
+		}
+
+		list = append(list, proto.CodeAction{
+			Title: title,
+			Kind:  &kind,
+			Data: CodeActionData{
+				Uri:  uri,
+				Type: t,
+				Line: rel.Start.Line,
+			},
+		})
+	}
+
+	persons := rel.Targets.Persons
+
+	if slices.ContainsFunc(persons, func(p *fm.Person) bool { return p.Num != nil }) && len(renumberEdits(persons)) > 0 {
+		add(CodeActionKindSourceRenumber, L("renumber_children"), RenumberChildrenSource)
+	}
+
+	if !slices.Equal(sortChildren(doc, persons, SortChildrenByNameSource), persons) {
+		add(CodeActionKindSourceSort, L("sort_children_by_name"), SortChildrenByNameSource)
+	}
+
+	add(CodeActionKindSourceSort, L("sort_children_by_birth"), SortChildrenByBirthSource)
+
+	return
+}
+
+func resolveSourceAction(data *CodeActionData) (changes map[Uri][]proto.TextEdit, err error) {
+	doc := GetDoc(data.Uri)
+
+	if doc == nil {
+		return nil, fmt.Errorf("document not found")
+	}
+
+	rel := findRelationByLine(doc, data.Line)
+
+	if rel == nil || rel.Targets == nil {
+		return nil, fmt.Errorf("relation not found")
+	}
+
+	persons := rel.Targets.Persons
+	var edits []proto.TextEdit
+
+	switch data.Type {
+	case RenumberChildrenSource:
+		edits = renumberEdits(persons)
+
+	case SortChildrenByNameSource, SortChildrenByBirthSource:
+		sorted := sortChildren(doc, persons, data.Type)
+
+		if sorted == nil {
+			return nil, fmt.Errorf("birth dates not found")
+		}
+
+		for i, p := range persons {
+			if sorted[i] == p {
+				continue
+			}
+
+			start := LocPosToPosition(p.Start)
+
+			if p.Num != nil {
+				start = TokenEndToPosition(p.Num)
+			}
+
+			text := personText(doc, sorted[i])
+
+			if p.Num != nil {
+				text = " " + text
+			}
+
+			edits = append(edits, proto.TextEdit{
+				Range: Range{
+					Start: start,
+					End:   LocPosToPosition(p.End),
+				},
+				NewText: text,
+			})
+		}
+
+		edits = append(edits, renumberEdits(persons)...)
+	}
+
+	doc.Version++
+	doc.NeedDiagnostic = true
+
+	changes = map[Uri][]proto.TextEdit{
+		doc.Uri: edits,
+	}
+
+	return
+}
+
+// renumberEdits fixes gaps and duplicates of children numbers,
+// next children with letters and the same number like "2a." and "2b." keep the same number
+func renumberEdits(persons []*fm.Person) (edits []proto.TextEdit) {
+	n := 0
+	prev := ""
+
+	for _, p := range persons {
+		if p.Num == nil {
+			n++
+			prev = ""
+			continue
+		}
+
+		num, suffix := parseChildNum(p.Num.Text)
+		group := ""
+
+		if suffix != "" {
+			group = strconv.Itoa(num)
+		}
+
+		if group == "" || group != prev {
+			n++
+		}
+
+		prev = group
+		text := strconv.Itoa(n) + suffix + "."
+
+		if p.Num.Text != text {
+			edits = append(edits, proto.TextEdit{
+				Range:   TokenToRange(p.Num),
+				NewText: text,
+			})
+		}
+	}
+
+	return
+}
+
+// sortChildren returns sorted copy of persons, unknown persons and persons without birth date stay at the end.
+// Returns nil when sorting by birth date and there is no birth dates at all
+func sortChildren(doc *Doc, persons []*fm.Person, by uint8) []*fm.Person {
+	keys := make(map[*fm.Person]string)
+	dates := make(map[*fm.Person]int)
+	hasDates := false
+
+	for _, p := range persons {
+		if p.Name == nil {
+			continue
+		}
+
+		keys[p] = strings.ToLower(p.Name.Text)
+
+		if by != SortChildrenByBirthSource {
+			continue
+		}
+
+		if mem := root.GetMemberByToken(doc.Uri, p.Name); mem != nil {
+			dates[p] = mem.GetBirthDate()
+			hasDates = hasDates || dates[p] > 0
+		}
+	}
+
+	if by == SortChildrenByBirthSource && !hasDates {
+		return nil
+	}
+
+	sorted := slices.Clone(persons)
+
+	slices.SortStableFunc(sorted, func(a, b *fm.Person) int {
+		if by == SortChildrenByBirthSource {
+			da, db := dates[a], dates[b]
+
+			if (da > 0) != (db > 0) {
+				return db - da
+			}
+
+			return cmp.Compare(da, db)
+		}
+
+		ka, okA := keys[a]
+		kb, okB := keys[b]
+
+		if okA != okB {
+			if okA {
+				return -1
+			}
+
+			return 1
+		}
+
+		return strings.Compare(ka, kb)
+	})
+
+	return sorted
+}
+
+func findRelationByLine(doc *Doc, line int) *fm.Relation {
+	for _, f := range doc.Root.Families {
+		for _, rel := range f.Relations {
+			if rel.Start.Line == line {
+				return rel
+			}
+		}
+	}
+
+	return nil
+}
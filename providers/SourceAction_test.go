@@ -0,0 +1,109 @@
+package providers
+
+import "testing"
+
+func TestRenumberEdits(t *testing.T) {
+	list := []struct {
+		text   string
+		expect string
+	}{
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n3. Rose\n7. Tom\n",
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3. Tom\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n2. Tom\n",
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3. Tom\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n3a. Rose\n3b. Tom\n5. Ann\n",
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2a. Rose\n2b. Tom\n3. Ann\n",
+		},
+		{
+			text:   "Potter\n\nJames + Lily =\n1. Harry\n2a. Rose\n3a. Tom\n",
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2a. Rose\n3a. Tom\n",
+		},
+	}
+
+	for _, item := range list {
+		folder := testFolder(t, map[string]string{"Potter.fml": item.text})
+		rel := GetDoc(folder + "/Potter.fml").Root.Families[0].Relations[0]
+
+		res := applyTextEdits(item.text, renumberEdits(rel.Targets.Persons))
+
+		if res != item.expect {
+			t.Errorf("%q != %q", res, item.expect)
+		}
+	}
+}
+
+func TestSortChildren(t *testing.T) {
+	text := "Potter\n\nJames + Lily =\n1. Rose\n2. Harry\n3. Tom\n"
+
+	list := []struct {
+		name   string
+		by     uint8
+		files  map[string]string
+		expect string
+	}{
+		{
+			name:   "by name",
+			by:     SortChildrenByNameSource,
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n3. Tom\n",
+		},
+		{
+			name: "by birth",
+			by:   SortChildrenByBirthSource,
+			files: map[string]string{
+				"Potter/Rose.md":  "born: 1985\n",
+				"Potter/Harry.md": "born: 1980-07-31\n",
+				"Potter/Tom.md":   "born: 1982\n",
+			},
+			expect: "Potter\n\nJames + Lily =\n1. Harry\n2. Tom\n3. Rose\n",
+		},
+		{
+			name: "by birth without some dates",
+			by:   SortChildrenByBirthSource,
+			files: map[string]string{
+				"Potter/Tom.md": "born: 1982\n",
+			},
+			expect: "Potter\n\nJames + Lily =\n1. Tom\n2. Rose\n3. Harry\n",
+		},
+	}
+
+	for _, item := range list {
+		files := map[string]string{"Potter.fml": text}
+
+		for name, md := range item.files {
+			files[name] = md
+		}
+
+		folder := testFolder(t, files)
+		uri := folder + "/Potter.fml"
+
+		changes, err := resolveSourceAction(&CodeActionData{Uri: uri, Type: item.by, Line: 2})
+
+		if err != nil {
+			t.Errorf("%s: %s", item.name, err)
+			continue
+		}
+
+		res := applyTextEdits(text, changes[uri])
+
+		if res != item.expect {
+			t.Errorf("%s: %q != %q", item.name, res, item.expect)
+		}
+	}
+
+	folder := testFolder(t, map[string]string{"Potter.fml": text})
+	uri := folder + "/Potter.fml"
+	rel := GetDoc(uri).Root.Families[0].Relations[0]
+
+	if sorted := sortChildren(GetDoc(uri), rel.Targets.Persons, SortChildrenByBirthSource); sorted != nil {
+		t.Errorf("expected nil without birth dates")
+	}
+
+	if _, err := resolveSourceAction(&CodeActionData{Uri: uri, Type: SortChildrenByBirthSource, Line: 2}); err == nil {
+		t.Errorf("expected error without birth dates")
+	}
+}
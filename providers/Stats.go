@@ -0,0 +1,258 @@
+package providers
+
+import (
+	"cmp"
+	"encoding/json"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+
+	. "github.com/redexp/familymarkup-lsp/state"
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+// count of items in top lists of statistics
+const StatsTopSize = 10
+
+func WorkspaceStats(_ *Ctx, _ *WorkspaceStatsParams) (res *WorkspaceStatsResult, err error) {
+	err = root.UpdateDirty()
+
+	if err != nil {
+		return
+	}
+
+	res = &WorkspaceStatsResult{
+		Unresolved:      len(root.UnknownRefs),
+		LargestFamilies: make([]StatsItem, 0),
+		CommonNames:     make([]StatsItem, 0),
+		FilesWithErrors: make([]Uri, 0),
+		Folders:         make([]*FolderStats, 0),
+	}
+
+	folders := make(map[Uri]*FolderStats)
+	families := make(map[string]int)
+	names := make(map[string]int)
+
+	getFolder := func(uri Uri) *FolderStats {
+		folder := root.FindFolder(uri)
+		stats, ok := folders[folder]
+
+		if !ok {
+			stats = &FolderStats{Folder: folder}
+			folders[folder] = stats
+		}
+
+		return stats
+	}
+
+	for f := range root.FamilyIter() {
+		folder := getFolder(f.Uri)
+		count := 0
+
+		for _, counts := range []*StatsCounts{&res.StatsCounts, &folder.StatsCounts} {
+			counts.Families++
+			counts.Relations += len(f.Node.Relations)
+		}
+
+		for _, rel := range f.Node.Relations {
+			for p := range rel.PersonsIter() {
+				if p.Unknown == nil {
+					continue
+				}
+
+				res.Unknown++
+				folder.Unknown++
+			}
+		}
+
+		for mem := range f.MembersIter() {
+			if mem.Origin != nil {
+				continue
+			}
+
+			count++
+			names[mem.Name]++
+
+			for _, counts := range []*StatsCounts{&res.StatsCounts, &folder.StatsCounts} {
+				counts.Members++
+
+				if mem.InfoUri != "" {
+					counts.Biographies++
+				}
+			}
+		}
+
+		families[f.Name+" ("+filepath.Base(f.Uri)+")"] = count
+	}
+
+	res.Generations = getDeepestGeneration()
+	res.LargestFamilies = getTopStats(families)
+	res.CommonNames = getTopStats(names)
+
+	for uri := range root.Docs {
+		for _, d := range GetDiagnostics(uri) {
+			if d.Severity != nil && *d.Severity == proto.DiagnosticSeverityError {
+				res.FilesWithErrors = append(res.FilesWithErrors, uri)
+				break
+			}
+		}
+	}
+
+	slices.Sort(res.FilesWithErrors)
+
+	for _, folder := range slices.Sorted(maps.Keys(folders)) {
+		res.Folders = append(res.Folders, folders[folder])
+	}
+
+	return
+}
+
+// getDeepestGeneration returns max count of generations from a person without parents to the youngest descendant
+func getDeepestGeneration() (res int) {
+	depths := make(map[*Member]int)
+
+	var getDepth func(mem *Member) int
+
+	getDepth = func(mem *Member) int {
+		if depth, ok := depths[mem]; ok {
+			return depth
+		}
+
+		// zero depth protects from cycles of wrong relations
+		depths[mem] = 0
+		depth := 1
+
+		for parent := range root.ParentsIter(mem) {
+			depth = max(depth, getDepth(parent)+1)
+		}
+
+		depths[mem] = depth
+
+		return depth
+	}
+
+	for mem := range root.MembersIter() {
+		res = max(res, getDepth(mem.GetOrigin()))
+	}
+
+	return
+}
+
+// getTopStats returns StatsTopSize items with the biggest counts
+func getTopStats(counts map[string]int) []StatsItem {
+	list := make([]StatsItem, 0, len(counts))
+
+	for name, count := range counts {
+		list = append(list, StatsItem{Name: name, Count: count})
+	}
+
+	slices.SortFunc(list, func(a, b StatsItem) int {
+		return cmp.Or(b.Count-a.Count, cmp.Compare(a.Name, b.Name))
+	})
+
+	return list[:min(len(list), StatsTopSize)]
+}
+
+// PrintStats writes statistics of folders in JSON format, used by "stats" command of CLI
+func PrintStats(paths []string, w io.Writer) (err error) {
+	err = loadFolders(paths)
+
+	if err != nil {
+		return
+	}
+
+	res, err := WorkspaceStats(nil, nil)
+
+	if err != nil {
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(res)
+}
+
+// loadFolders creates root with folders from command line, current folder by default
+func loadFolders(paths []string) (err error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	folders := make([]Uri, len(paths))
+
+	for i, path := range paths {
+		path, err = filepath.Abs(path)
+
+		if err != nil {
+			return
+		}
+
+		folders[i] = ToUri(path)
+	}
+
+	root = CreateRoot()
+
+	return root.SetFolders(folders)
+}
+
+type StatsCounts struct {
+	Families    int `json:"families"`
+	Members     int `json:"members"`
+	Relations   int `json:"relations"`
+	Unknown     int `json:"unknown"`
+	Biographies int `json:"biographies"`
+}
+
+type StatsItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+type FolderStats struct {
+	StatsCounts
+
+	Folder Uri `json:"folder"`
+}
+
+type WorkspaceStatsResult struct {
+	StatsCounts
+
+	Unresolved      int            `json:"unresolved"`
+	Generations     int            `json:"generations"`
+	LargestFamilies []StatsItem    `json:"largestFamilies"`
+	CommonNames     []StatsItem    `json:"commonNames"`
+	FilesWithErrors []Uri          `json:"filesWithErrors"`
+	Folders         []*FolderStats `json:"folders"`
+}
+
+type StatsHandlers struct {
+	Workspace WorkspaceStatsFunc
+}
+
+func (req *StatsHandlers) Handle(ctx *Ctx) (res any, validMethod bool, validParams bool, err error) {
+	switch ctx.Method {
+	case WorkspaceStatsMethod:
+		validMethod = true
+
+		var params WorkspaceStatsParams
+		if len(ctx.Params) == 0 {
+			validParams = true
+			res, err = req.Workspace(ctx, &params)
+		} else if err = json.Unmarshal(ctx.Params, &params); err == nil {
+			validParams = true
+			res, err = req.Workspace(ctx, &params)
+		}
+	}
+
+	return
+}
+
+const WorkspaceStatsMethod = "stats/workspace"
+
+type WorkspaceStatsParams struct{}
+
+type WorkspaceStatsFunc func(*Ctx, *WorkspaceStatsParams) (*WorkspaceStatsResult, error)
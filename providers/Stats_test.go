@@ -0,0 +1,90 @@
+package providers
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+func TestGetTopStats(t *testing.T) {
+	counts := map[string]int{}
+
+	for i, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		counts[name] = i % 3
+	}
+
+	list := getTopStats(counts)
+
+	if len(list) != StatsTopSize {
+		t.Fatalf("size: %d", len(list))
+	}
+
+	if list[0].Name != "c" || list[0].Count != 2 || list[len(list)-1].Count != 0 {
+		t.Errorf("list: %v", list)
+	}
+}
+
+func TestWorkspaceStats(t *testing.T) {
+	a := writeTestFiles(t, map[string]string{
+		"Potter.fml":      "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny Weasley =\n1. Albus\n2. ?\n",
+		"Potter/Harry.md": "# Harry\n",
+	})
+
+	b := writeTestFiles(t, map[string]string{
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ginny\n2. Ron\n3. Lily\n\nRon + Hermione Granger =\n1. Rose\n",
+	})
+
+	err := loadFolders([]string{a, b})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := WorkspaceStats(nil, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := StatsCounts{Families: 2, Members: 11, Relations: 4, Unknown: 1, Biographies: 1}
+
+	if res.StatsCounts != expect {
+		t.Errorf("counts: %+v", res.StatsCounts)
+	}
+
+	// Ginny Weasley from other folder and Hermione Granger without family
+	if res.Unresolved != 2 || res.Generations != 3 {
+		t.Errorf("unresolved: %d, generations: %d", res.Unresolved, res.Generations)
+	}
+
+	families := []StatsItem{{"Weasley (Weasley.fml)", 7}, {"Potter (Potter.fml)", 4}}
+
+	if !slices.Equal(res.LargestFamilies, families) {
+		t.Errorf("largest families: %v", res.LargestFamilies)
+	}
+
+	if len(res.CommonNames) != StatsTopSize || res.CommonNames[0] != (StatsItem{"Lily", 2}) || res.CommonNames[1] != (StatsItem{"Albus", 1}) {
+		t.Errorf("common names: %v", res.CommonNames)
+	}
+
+	if !slices.Equal(res.FilesWithErrors, []Uri{ToUri(b) + "/Weasley.fml"}) {
+		t.Errorf("files with errors: %v", res.FilesWithErrors)
+	}
+
+	folders := []FolderStats{
+		{StatsCounts: StatsCounts{Families: 1, Members: 4, Relations: 2, Unknown: 1, Biographies: 1}, Folder: ToUri(a)},
+		{StatsCounts: StatsCounts{Families: 1, Members: 7, Relations: 2}, Folder: ToUri(b)},
+	}
+
+	if len(res.Folders) != len(folders) {
+		t.Fatalf("folders: %v", res.Folders)
+	}
+
+	for i, folder := range res.Folders {
+		if *folder != folders[i] {
+			t.Errorf("folder %d: %+v", i, *folder)
+		}
+	}
+}
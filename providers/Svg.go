@@ -14,8 +14,14 @@ import (
 )
 
 func SvgFamilies(_ *Ctx, params *SvgFamiliesParams) (SvgFamiliesResult, error) {
+	fontRatio := params.FontRatio
+
+	if fontRatio == 0 && params.URI != "" {
+		fontRatio = root.GetConfig(NormalizeUri(params.URI)).Layout.FontRatio
+	}
+
 	families, relations := layout.Align(root, layout.AlignParams{
-		FontRatio: params.FontRatio,
+		FontRatio: fontRatio,
 	})
 
 	return SvgFamiliesResult{
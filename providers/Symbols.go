@@ -3,6 +3,7 @@ package providers
 import (
 	"encoding/json"
 	"fmt"
+	"iter"
 	"slices"
 	"strings"
 	"unicode"
@@ -16,6 +17,7 @@ import (
 
 const SymbolKindFamily = proto.SymbolKindConstant
 const SymbolKindMember = proto.SymbolKindField
+const SymbolKindRelation = proto.SymbolKindStruct
 
 func DocSymbols(_ *Ctx, params *proto.DocumentSymbolParams) (res any, err error) {
 	uri := NormalizeUri(params.TextDocument.URI)
@@ -37,16 +39,12 @@ func DocSymbols(_ *Ctx, params *proto.DocumentSymbolParams) (res any, err error)
 			Children:       make([]proto.DocumentSymbol, 0),
 		}
 
-		for mem := range f.MembersIter() {
-			r := LocToRange(mem.Person.Name.Loc())
-
-			symbol.Children = append(symbol.Children, proto.DocumentSymbol{
-				Kind:           SymbolKindMember,
-				Name:           mem.Name,
-				Detail:         new(fmt.Sprintf("%s %s", f.Name, mem.Name)),
-				Range:          r,
-				SelectionRange: r,
-			})
+		if len(f.Aliases) > 0 {
+			symbol.Detail = new(strings.Join(f.Aliases, ", "))
+		}
+
+		for _, rel := range f.Node.Relations {
+			symbol.Children = append(symbol.Children, getRelationSymbol(uri, rel))
 		}
 
 		list = append(list, symbol)
@@ -55,6 +53,74 @@ func DocSymbols(_ *Ctx, params *proto.DocumentSymbolParams) (res any, err error)
 	return list, nil
 }
 
+// getRelationSymbol returns symbol of relation labelled with sources and arrow,
+// full names of partners as detail and children as symbols
+func getRelationSymbol(uri Uri, rel *fm.Relation) proto.DocumentSymbol {
+	name := rel.Sources.Format()
+
+	if rel.Arrow != nil {
+		name += " " + rel.Arrow.Text
+	}
+
+	if rel.Label != nil {
+		name += " " + rel.Label.Text
+	}
+
+	symbol := proto.DocumentSymbol{
+		Kind:           SymbolKindRelation,
+		Name:           name,
+		Range:          LocToRange(rel.Loc),
+		SelectionRange: LocToRange(rel.Sources.Loc),
+		Children:       make([]proto.DocumentSymbol, 0),
+	}
+
+	partners := make([]string, 0, len(rel.Sources.Persons))
+
+	for _, p := range rel.Sources.Persons {
+		if p.Name == nil {
+			continue
+		}
+
+		if mem := root.GetMemberByToken(uri, p.Name); mem != nil {
+			mem = mem.GetOrigin()
+			partners = append(partners, mem.Name+" "+mem.GetSurname())
+		}
+	}
+
+	if len(partners) > 0 {
+		symbol.Detail = new(strings.Join(partners, " + "))
+	}
+
+	if rel.Targets == nil {
+		return symbol
+	}
+
+	for _, p := range rel.Targets.Persons {
+		if p.Name == nil {
+			continue
+		}
+
+		child := proto.DocumentSymbol{
+			Kind:           SymbolKindMember,
+			Name:           p.Name.Text,
+			Range:          LocToRange(p.Loc),
+			SelectionRange: TokenToRange(p.Name),
+		}
+
+		if p.Surname != nil {
+			child.Name += " " + p.Surname.Text
+		}
+
+		if len(p.Aliases) > 0 {
+			child.Detail = new(strings.Join(TokensToStrings(p.Aliases), ", "))
+		}
+
+		symbol.Children = append(symbol.Children, child)
+	}
+
+	return symbol
+}
+
 func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation, err error) {
 	defer func() {
 		if err != nil {
@@ -71,6 +137,10 @@ func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation
 		}
 
 		slices.SortFunc(list, func(a, b SymbolInformation) int {
+			if a.score != b.score {
+				return b.score - a.score
+			}
+
 			dir := strings.Compare(a.Name, b.Name)
 
 			if dir == 0 && a.Kind == SymbolKindMember && b.Kind == SymbolKindMember {
@@ -109,12 +179,13 @@ func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation
 	parts := splitQuery(params.Query)
 	count := len(parts)
 
-	addFamily := func(f *Family, name string) {
+	addFamily := func(f *Family, name string, score int) {
 		if params.OnlyMembers {
 			return
 		}
 
 		list = append(list, SymbolInformation{
+			score: score,
 			SymbolInformation: proto.SymbolInformation{
 				Kind: SymbolKindFamily,
 				Name: name,
@@ -126,9 +197,10 @@ func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation
 		})
 	}
 
-	addMember := func(f *Family, mem *Member, name string, surname string) {
+	addMember := func(f *Family, mem *Member, name string, surname string, score int) {
 		list = append(list, SymbolInformation{
 			member: mem,
+			score:  score,
 			SymbolInformation: proto.SymbolInformation{
 				Kind:          SymbolKindMember,
 				Name:          name,
@@ -143,30 +215,24 @@ func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation
 
 	if count == 0 {
 		for f := range root.FamilyIter() {
-			addFamily(f, f.Name)
+			addFamily(f, f.Name, 0)
 
 			for mem := range f.MembersIter() {
-				addMember(f, mem, mem.Name, f.Name)
+				addMember(f, mem, mem.Name, f.Name, 0)
 			}
 		}
 
 		return
 	}
 
-	surnameQuery := parts[count-1]
+	nameQuery := createNameQuery(parts[0])
+	surnameQuery := createNameQuery(parts[count-1])
 
 	for f := range root.FamilyIter() {
-		surname := ""
-
-		for name := range f.NamesIter() {
-			if startsWith(name, surnameQuery) {
-				surname = name
-				break
-			}
-		}
+		surname, surnameScore := surnameQuery.best(f.NamesIter())
 
 		if count == 1 && surname != "" {
-			addFamily(f, surname)
+			addFamily(f, surname, surnameScore)
 			continue
 		}
 
@@ -175,26 +241,24 @@ func AllSymbols(_ *Ctx, params *WorkspaceSymbolParams) (list []SymbolInformation
 		}
 
 		for mem := range f.MembersIter() {
-			for name := range mem.NamesIter() {
-				if !startsWith(name, parts[0]) {
-					continue
-				}
+			name, score := nameQuery.best(mem.NamesIter())
 
-				if params.ExactMatch && surname != "" {
-					addMember(f, mem, name, surname)
-					break
-				}
-
-				title := name
+			if name == "" {
+				continue
+			}
 
-				if surname != "" {
-					title = fmt.Sprintf("%s %s", name, surname)
-				}
+			if params.ExactMatch && surname != "" {
+				addMember(f, mem, name, surname, score+surnameScore)
+				continue
+			}
 
-				addMember(f, mem, title, f.Name)
+			title := name
 
-				break
+			if surname != "" {
+				title = fmt.Sprintf("%s %s", name, surname)
 			}
+
+			addMember(f, mem, title, f.Name, score+surnameScore)
 		}
 	}
 
@@ -313,8 +377,81 @@ func splitQuery(query string) []string {
 	return list
 }
 
-func startsWith(name string, q string) bool {
-	return strings.HasPrefix(strings.ToLower(name), q)
+// nameQuery is a lowercase part of workspace symbol query with its NameKey
+type nameQuery struct {
+	text string
+	key  string
+}
+
+func createNameQuery(text string) nameQuery {
+	return nameQuery{
+		text: text,
+		key:  NameKey(text),
+	}
+}
+
+// score returns how good the name matches the query, zero when it doesn't match.
+// Names which start with the query go first, then names which start with the query
+// in another script or spelling (Olena and Олена), then names which contain it
+// and at the end names with typos
+func (q nameQuery) score(name string) int {
+	lower := strings.ToLower(name)
+
+	if lower == q.text {
+		return 100
+	}
+
+	if strings.HasPrefix(lower, q.text) {
+		return 90
+	}
+
+	key := NameKey(name)
+
+	if key == q.key {
+		return 80
+	}
+
+	if strings.HasPrefix(key, q.key) {
+		return 70
+	}
+
+	if strings.Contains(key, q.key) {
+		return 40
+	}
+
+	size := len([]rune(q.key))
+
+	if size < 3 {
+		return 0
+	}
+
+	chars := []rune(key)
+	allowed := min(2, 1+size/6)
+	dist := allowed + 1
+
+	for n := size - 1; n <= size+1 && n <= len(chars); n++ {
+		dist = min(dist, EditDistance(string(chars[:n]), q.key))
+	}
+
+	if dist > allowed {
+		return 0
+	}
+
+	return 30 - dist*10
+}
+
+// best returns name with the highest score and the score
+func (q nameQuery) best(names iter.Seq[string]) (res string, score int) {
+	for name := range names {
+		s := q.score(name)
+
+		if s > score {
+			res = name
+			score = s
+		}
+	}
+
+	return
 }
 
 type WorkspaceSymbolOptions struct {
@@ -332,6 +469,7 @@ type SymbolInformation struct {
 
 	Details string `json:"details,omitempty"`
 	member  *Member
+	score   int
 }
 
 type WorkspaceHandler struct {
@@ -0,0 +1,29 @@
+package providers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNameQueryScore(t *testing.T) {
+	q := createNameQuery("olena")
+
+	list := []string{"Olena", "Олена", "Aolena", "Olenka", "Oleva", "Harry"}
+	scores := make([]int, len(list))
+
+	for i, name := range list {
+		scores[i] = q.score(name)
+	}
+
+	if !slices.IsSortedFunc(scores[:5], func(a, b int) int { return b - a }) || scores[4] == 0 || scores[5] != 0 {
+		t.Errorf("scores: %v", scores)
+	}
+
+	if s := createNameQuery("oleksandr").score("Олександр"); s == 0 {
+		t.Errorf("transliteration score: %d", s)
+	}
+
+	if name, _ := createNameQuery("har").best(slices.Values([]string{"Ron", "Harry"})); name != "Harry" {
+		t.Errorf("best: %s", name)
+	}
+}
@@ -2,7 +2,6 @@ package providers
 
 import (
 	. "github.com/redexp/familymarkup-lsp/state"
-	"github.com/redexp/familymarkup-lsp/utils"
 	proto "github.com/tliron/glsp/protocol_3_16"
 	"strings"
 	"sync"
@@ -11,7 +10,7 @@ import (
 func DocOpen(_ *Ctx, params *proto.DidOpenTextDocumentParams) (err error) {
 	uri := NormalizeUri(params.TextDocument.URI)
 
-	if !utils.IsFamilyUri(uri) {
+	if !root.IsFamilyUri(uri) || root.IsIgnored(uri) {
 		return
 	}
 
@@ -77,20 +76,45 @@ func DocRename(_ *Ctx, params *proto.RenameFilesParams) error {
 		if ok {
 			root.DirtyUris.Set(oldUri, UriDelete)
 
-			if _, ok = root.Docs[newUri]; !ok {
+			if _, ok = root.Docs[newUri]; !ok && !root.IsIgnored(newUri) {
 				root.DirtyUris.SetText(newUri, UriCreate, doc.Text)
 			}
 
 			continue
 		}
 
-		if utils.IsMarkdownUri(oldUri) {
+		if root.IsMarkdownUri(oldUri) {
 			root.DirtyUris.Set(oldUri, UriDelete)
-			root.DirtyUris.Set(newUri, UriCreate)
+
+			if root.IsIgnored(newUri) {
+				continue
+			}
+
+			text, err := GetText(newUri)
+
+			if err != nil {
+				return err
+			}
+
+			root.DirtyUris.SetText(newUri, UriCreate, text)
+
 			continue
 		}
 
-		if utils.IsFamilyUri(oldUri) {
+		// ignored family file was not indexed
+		if root.IsFamilyUri(oldUri) {
+			if root.IsIgnored(newUri) {
+				continue
+			}
+
+			text, err := GetText(newUri)
+
+			if err != nil {
+				return err
+			}
+
+			root.DirtyUris.SetText(newUri, UriCreate, text)
+
 			continue
 		}
 
@@ -113,7 +137,7 @@ func DocRename(_ *Ctx, params *proto.RenameFilesParams) error {
 
 				newUri = strings.Replace(uri, oldFolder, newFolder, 1)
 
-				if _, ok := root.Docs[newUri]; ok {
+				if _, ok := root.Docs[newUri]; ok || root.IsIgnored(newUri) {
 					continue
 				}
 
@@ -133,6 +157,10 @@ func DocRename(_ *Ctx, params *proto.RenameFilesParams) error {
 
 				newUri = strings.Replace(uri, oldFolder, newFolder, 1)
 
+				if root.IsIgnored(newUri) {
+					continue
+				}
+
 				root.UnknownFiles[newUri] = item
 			}
 		}()
@@ -143,6 +171,10 @@ func DocRename(_ *Ctx, params *proto.RenameFilesParams) error {
 			for mem := range root.MembersIter() {
 				if strings.HasPrefix(mem.InfoUri, oldFolder) {
 					mem.InfoUri = strings.Replace(mem.InfoUri, oldFolder, newFolder, 1)
+
+					if root.IsIgnored(mem.InfoUri) {
+						mem.InfoUri = ""
+					}
 				}
 			}
 		}()
@@ -162,12 +194,12 @@ func DocDelete(_ *Ctx, params *proto.DeleteFilesParams) error {
 			continue
 		}
 
-		if utils.IsMarkdownUri(uri) {
+		if root.IsMarkdownUri(uri) {
 			root.DirtyUris.Set(uri, UriDelete)
 			continue
 		}
 
-		if utils.IsFamilyUri(uri) {
+		if root.IsFamilyUri(uri) {
 			continue
 		}
 
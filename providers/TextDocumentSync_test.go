@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/utils"
+	proto "github.com/tliron/glsp/protocol_3_16"
+)
+
+func TestDocRenameIgnoredFamily(t *testing.T) {
+	folder := testFolder(t, map[string]string{
+		".familymarkupignore": "drafts/\n",
+		"drafts/Potter.fml":   "Potter\n\nJames + Lily =\n1. Harry\n",
+	})
+
+	oldUri := folder + "/drafts/Potter.fml"
+	newUri := folder + "/Potter.fml"
+
+	if GetDoc(oldUri) != nil {
+		t.Fatal("ignored file is indexed")
+	}
+
+	oldPath, _ := UriToPath(oldUri)
+	newPath, _ := UriToPath(newUri)
+
+	if err := os.Rename(oldPath, newPath); err != nil {
+		t.Fatal(err)
+	}
+
+	err := DocRename(nil, &proto.RenameFilesParams{
+		Files: []proto.FileRename{{OldURI: oldUri, NewURI: newUri}},
+	})
+
+	if err == nil {
+		err = root.UpdateDirty()
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if GetDoc(newUri) == nil || root.FindFamily("Potter") == nil {
+		t.Error("renamed file is not indexed")
+	}
+}
@@ -3,12 +3,15 @@ package providers
 import (
 	"github.com/redexp/familymarkup-lsp/state"
 	"github.com/tliron/glsp"
+	proto "github.com/tliron/glsp/protocol_3_16"
 )
 
 var (
 	root *state.Root
 )
 
-var warnChildrenWithoutRelations = false
+var clientConfig ClientConfiguration
+
+var clientCapabilities proto.ClientCapabilities
 
 type Ctx = glsp.Context
@@ -0,0 +1,144 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+const ConfigFileName = ".familymarkup.json"
+
+const (
+	InfoLayoutFile  = "file"  // Surname/Name.md
+	InfoLayoutIndex = "index" // Surname/Name/index.md
+)
+
+// Config is the content of optional ConfigFileName at the root of workspace folder
+type Config struct {
+	Extensions                   ExtConfig         `json:"extensions"`
+	Ignore                       []string          `json:"ignore"`
+	Info                         InfoConfig        `json:"info"`
+	Diagnostics                  map[string]string `json:"diagnostics"`
+	Locale                       string            `json:"locale"`
+	WarnChildrenWithoutRelations *bool             `json:"warnChildrenWithoutRelations"`
+	Layout                       LayoutConfig      `json:"layout"`
+}
+
+type ExtConfig struct {
+	Family   []string `json:"family"`
+	Markdown []string `json:"markdown"`
+}
+
+type InfoConfig struct {
+	Folder string `json:"folder"`
+	Layout string `json:"layout"`
+}
+
+type LayoutConfig struct {
+	FontRatio float64 `json:"fontRatio"`
+}
+
+type Configs map[Uri]*Config
+
+func CreateConfig() *Config {
+	return &Config{
+		Extensions: ExtConfig{
+			Family:   FamilyExt,
+			Markdown: MarkdownExt,
+		},
+		Info: InfoConfig{
+			Layout: InfoLayoutFile,
+		},
+		Diagnostics: make(map[string]string),
+	}
+}
+
+func LoadConfig(folder Uri) (config *Config, err error) {
+	config = CreateConfig()
+
+	text, err := GetText(toFolderUri(folder) + ConfigFileName)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+
+	if err != nil {
+		return
+	}
+
+	var src Config
+
+	err = json.Unmarshal([]byte(text), &src)
+
+	if err != nil {
+		return config, fmt.Errorf("%s: %w", ConfigFileName, err)
+	}
+
+	config.Merge(&src)
+
+	return
+}
+
+func (config *Config) Merge(src *Config) {
+	if len(src.Extensions.Family) > 0 {
+		config.Extensions.Family = normalizeExt(src.Extensions.Family)
+	}
+
+	if len(src.Extensions.Markdown) > 0 {
+		config.Extensions.Markdown = normalizeExt(src.Extensions.Markdown)
+	}
+
+	config.Ignore = append(config.Ignore, src.Ignore...)
+
+	if src.Info.Folder != "" {
+		config.Info.Folder = filepath.ToSlash(filepath.Clean(src.Info.Folder))
+	}
+
+	if src.Info.Layout != "" {
+		config.Info.Layout = src.Info.Layout
+	}
+
+	for key, value := range src.Diagnostics {
+		config.Diagnostics[key] = value
+	}
+
+	if src.Locale != "" {
+		config.Locale = src.Locale
+	}
+
+	if src.WarnChildrenWithoutRelations != nil {
+		config.WarnChildrenWithoutRelations = src.WarnChildrenWithoutRelations
+	}
+
+	if src.Layout.FontRatio > 0 {
+		config.Layout.FontRatio = src.Layout.FontRatio
+	}
+}
+
+func (config *Config) AllExt() []string {
+	return slices.Concat(config.Extensions.Family, config.Extensions.Markdown)
+}
+
+func (config *Config) IsFamilyUri(uri Uri) bool {
+	return slices.Contains(config.Extensions.Family, Ext(uri))
+}
+
+func (config *Config) IsMarkdownUri(uri Uri) bool {
+	return slices.Contains(config.Extensions.Markdown, Ext(uri))
+}
+
+func normalizeExt(list []string) []string {
+	res := make([]string, len(list))
+
+	for i, ext := range list {
+		res[i] = Ext("." + ext)
+	}
+
+	return res
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := LoadConfig(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(config.Extensions.Family, []string{"fml", "family"}) || config.Info.Layout != InfoLayoutFile {
+		t.Errorf("default config: %+v", config)
+	}
+
+	text := `{
+		"extensions": {"family": [".FML"]},
+		"info": {"folder": "./bio/"},
+		"diagnostics": {"duplicate-name": "off"},
+		"layout": {"fontRatio": 0.5}
+	}`
+
+	err = os.WriteFile(filepath.Join(dir, ConfigFileName), []byte(text), 0644)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err = LoadConfig(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(config.Extensions.Family, []string{"fml"}) {
+		t.Errorf("family ext: %v", config.Extensions.Family)
+	}
+
+	if !slices.Equal(config.Extensions.Markdown, []string{"md", "mdx"}) {
+		t.Errorf("markdown ext: %v", config.Extensions.Markdown)
+	}
+
+	if config.Info.Folder != "bio" {
+		t.Errorf("info folder: %s", config.Info.Folder)
+	}
+
+	if config.Diagnostics["duplicate-name"] != "off" || config.Layout.FontRatio != 0.5 {
+		t.Errorf("config: %+v", config)
+	}
+
+	if !config.IsFamilyUri("file:///tmp/Potter.fml") || config.IsFamilyUri("file:///tmp/Potter.family") {
+		t.Error("IsFamilyUri")
+	}
+
+	_ = os.WriteFile(filepath.Join(dir, ConfigFileName), []byte("{"), 0644)
+
+	_, err = LoadConfig(dir)
+
+	if err == nil {
+		t.Error("should return error")
+	}
+}
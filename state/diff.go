@@ -0,0 +1,377 @@
+package state
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	fm "github.com/redexp/familymarkup-parser"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+// types of DiffChange
+const (
+	DiffFamilyAdded       = "family-added"
+	DiffFamilyRemoved     = "family-removed"
+	DiffPersonAdded       = "person-added"
+	DiffPersonRemoved     = "person-removed"
+	DiffPersonMoved       = "person-moved"
+	DiffAliasAdded        = "alias-added"
+	DiffAliasRemoved      = "alias-removed"
+	DiffRelationAdded     = "relation-added"
+	DiffRelationRemoved   = "relation-removed"
+	DiffRelationChanged   = "relation-changed"
+	DiffChildrenReordered = "children-reordered"
+)
+
+// DiffChange is a change of the family tree model between two versions
+type DiffChange struct {
+	Type string `json:"type"`
+	// family of person or relation, new family of moved person
+	Family string `json:"family"`
+	// name of person or relation like "James + Lily ="
+	Name string `json:"name"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+func (c DiffChange) String() string {
+	text := fmt.Sprintf("%s %s/%s", c.Type, c.Family, c.Name)
+
+	if c.Old != "" || c.New != "" {
+		text += fmt.Sprintf(": %s -> %s", c.Old, c.New)
+	}
+
+	return text
+}
+
+// LoadSnapshot creates root of a folder or of a single family file, like a file of another git revision
+func LoadSnapshot(path string) (root *Root, err error) {
+	path, err = filepath.Abs(path)
+
+	if err != nil {
+		return
+	}
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return
+	}
+
+	root = CreateRoot()
+
+	if info.IsDir() {
+		err = root.SetFolders([]Uri{ToUri(path)})
+	} else {
+		folder := ToUri(filepath.Dir(path))
+
+		root.Folders.Set(folder)
+		root.Configs[folder], err = LoadConfig(folder)
+
+		if err != nil {
+			return
+		}
+
+		var text string
+
+		text, err = GetText(ToUri(path))
+
+		if err != nil {
+			return
+		}
+
+		root.DirtyUris.SetText(ToUri(path), UriCreate, text)
+	}
+
+	if err != nil {
+		return
+	}
+
+	err = root.UpdateDirty()
+
+	return
+}
+
+// Diff returns changes of families, persons, aliases and relations from root before to root after
+func Diff(before *Root, after *Root) (list []DiffChange) {
+	add := func(t string, family string, name string, from string, to string) {
+		list = append(list, DiffChange{
+			Type:   t,
+			Family: family,
+			Name:   name,
+			Old:    from,
+			New:    to,
+		})
+	}
+
+	oldFamilies := diffFamilies(before)
+	newFamilies := diffFamilies(after)
+
+	for _, name := range slices.Sorted(maps.Keys(oldFamilies)) {
+		if _, ok := newFamilies[name]; !ok {
+			add(DiffFamilyRemoved, name, "", "", "")
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(newFamilies)) {
+		if _, ok := oldFamilies[name]; !ok {
+			add(DiffFamilyAdded, name, "", "", "")
+		}
+	}
+
+	// persons
+
+	oldPersons := diffPersons(before)
+	newPersons := diffPersons(after)
+	var removed, added []string
+
+	for _, key := range slices.Sorted(maps.Keys(oldPersons)) {
+		if _, ok := newPersons[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(newPersons)) {
+		if _, ok := oldPersons[key]; !ok {
+			added = append(added, key)
+		}
+	}
+
+	// removed from one family and added to another with the same name and parents,
+	// persons without parents are too ambiguous to be paired
+	moved := make(map[string]string)
+
+	for _, from := range removed {
+		a := oldPersons[from]
+
+		for _, to := range added {
+			b := newPersons[to]
+
+			if moved[to] != "" || a.Parents == "" || NameKey(a.Member.Name) != NameKey(b.Member.Name) || a.Parents != b.Parents {
+				continue
+			}
+
+			moved[to] = from
+			moved[from] = to
+			add(DiffPersonMoved, b.Member.Family.Name, b.Member.Name, a.Member.Family.Name, b.Member.Family.Name)
+			break
+		}
+	}
+
+	for _, key := range removed {
+		if moved[key] == "" {
+			mem := oldPersons[key].Member
+			add(DiffPersonRemoved, mem.Family.Name, mem.Name, "", "")
+		}
+	}
+
+	for _, key := range added {
+		if moved[key] == "" {
+			mem := newPersons[key].Member
+			add(DiffPersonAdded, mem.Family.Name, mem.Name, "", "")
+		}
+	}
+
+	// aliases of persons in both versions, including moved ones
+
+	for _, key := range slices.Sorted(maps.Keys(newPersons)) {
+		b := newPersons[key].Member
+		from := key
+
+		if moved[key] != "" {
+			from = moved[key]
+		}
+
+		a, ok := oldPersons[from]
+
+		if !ok {
+			continue
+		}
+
+		for _, alias := range b.Aliases {
+			if !slices.Contains(a.Member.Aliases, alias) {
+				add(DiffAliasAdded, b.Family.Name, b.Name, "", alias)
+			}
+		}
+
+		for _, alias := range a.Member.Aliases {
+			if !slices.Contains(b.Aliases, alias) {
+				add(DiffAliasRemoved, b.Family.Name, b.Name, alias, "")
+			}
+		}
+	}
+
+	// relations of families of both versions, so removed families report their relations too
+
+	families := slices.Collect(maps.Keys(oldFamilies))
+
+	for name := range newFamilies {
+		if _, ok := oldFamilies[name]; !ok {
+			families = append(families, name)
+		}
+	}
+
+	slices.Sort(families)
+
+	for _, family := range families {
+		oldRelations := oldFamilies[family]
+		newRelations := newFamilies[family]
+
+		for _, key := range slices.Sorted(maps.Keys(oldRelations)) {
+			if _, ok := newRelations[key]; !ok {
+				add(DiffRelationRemoved, family, key, "", "")
+			}
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(newRelations)) {
+			b := newRelations[key]
+			a, ok := oldRelations[key]
+
+			if !ok {
+				add(DiffRelationAdded, family, key, "", "")
+				continue
+			}
+
+			if !slices.Equal(sortedClone(a.Children), sortedClone(b.Children)) {
+				add(DiffRelationChanged, family, key, strings.Join(a.Children, ", "), strings.Join(b.Children, ", "))
+			} else if !slices.Equal(a.Children, b.Children) {
+				add(DiffChildrenReordered, family, key, strings.Join(a.Children, ", "), strings.Join(b.Children, ", "))
+			}
+
+			if a.Label != b.Label {
+				add(DiffRelationChanged, family, key, a.Label, b.Label)
+			}
+		}
+	}
+
+	return
+}
+
+type diffPerson struct {
+	Member *Member
+	// names of parents, used to find moved persons
+	Parents string
+}
+
+type diffRelation struct {
+	Children []string
+	Label    string
+}
+
+// diffPersons returns persons by key "Surname/Name",
+// the second person with the same key in order of documents gets "#2" suffix
+func diffPersons(root *Root) map[string]diffPerson {
+	res := make(map[string]diffPerson)
+	var members []*Member
+
+	for mem := range root.MembersIter() {
+		if mem.Origin == nil {
+			members = append(members, mem)
+		}
+	}
+
+	slices.SortFunc(members, CompareMembers)
+
+	for _, mem := range members {
+		var parents []string
+
+		for parent := range root.ParentsIter(mem) {
+			parents = append(parents, NameKey(parent.Name+" "+parent.GetSurname()))
+		}
+
+		slices.Sort(parents)
+
+		res[uniqDiffKey(res, mem.Family.Name+"/"+mem.Name)] = diffPerson{
+			Member:  mem,
+			Parents: strings.Join(parents, " + "),
+		}
+	}
+
+	return res
+}
+
+// diffFamilies returns relations of families by key like "James + Lily =",
+// the second relation with the same key in order of documents gets "#2" suffix
+func diffFamilies(root *Root) map[string]map[string]diffRelation {
+	res := make(map[string]map[string]diffRelation)
+
+	for f := range root.FmFamilyIter() {
+		if f.Name == nil {
+			continue
+		}
+
+		relations, ok := res[f.Name.Text]
+
+		if !ok {
+			relations = make(map[string]diffRelation)
+			res[f.Name.Text] = relations
+		}
+
+		for _, rel := range f.Relations {
+			var sources []string
+
+			for _, p := range rel.Sources.Persons {
+				sources = append(sources, diffPersonName(p))
+			}
+
+			key := strings.Join(sources, " + ")
+
+			if rel.Arrow != nil {
+				key += " " + rel.Arrow.Text
+			}
+
+			item := diffRelation{}
+
+			if rel.Label != nil {
+				item.Label = rel.Label.Text
+			}
+
+			if rel.Targets != nil {
+				for _, p := range rel.Targets.Persons {
+					item.Children = append(item.Children, diffPersonName(p))
+				}
+			}
+
+			relations[uniqDiffKey(relations, key)] = item
+		}
+	}
+
+	return res
+}
+
+func uniqDiffKey[T any](items map[string]T, key string) string {
+	res := key
+
+	for i := 2; ; i++ {
+		if _, exist := items[res]; !exist {
+			return res
+		}
+
+		res = fmt.Sprintf("%s#%d", key, i)
+	}
+}
+
+func diffPersonName(p *fm.Person) string {
+	if p.Name == nil {
+		return "?"
+	}
+
+	if p.Surname != nil {
+		return p.Name.Text + " " + p.Surname.Text
+	}
+
+	return p.Name.Text
+}
+
+func sortedClone(list []string) []string {
+	list = slices.Clone(list)
+	slices.Sort(list)
+
+	return list
+}
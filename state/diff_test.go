@@ -0,0 +1,111 @@
+package state
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffChangeString(t *testing.T) {
+	list := map[string]DiffChange{
+		"person-added Potter/Albus":                              {Type: DiffPersonAdded, Family: "Potter", Name: "Albus"},
+		"person-moved Granger/Ron: Weasley -> Granger":           {Type: DiffPersonMoved, Family: "Granger", Name: "Ron", Old: "Weasley", New: "Granger"},
+		"children-reordered Potter/James + Lily =: A, B -> B, A": {Type: DiffChildrenReordered, Family: "Potter", Name: "James + Lily =", Old: "A, B", New: "B, A"},
+	}
+
+	for text, change := range list {
+		if change.String() != text {
+			t.Errorf("%q != %q", change.String(), text)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	before := testRoot(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n\n? + ? =\n1. Tom\n\n? + ? =\n1. Ann\n",
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ron\n2. Ginny\n",
+		"Dursley.fml": "Dursley\n\nVernon + Petunia =\n1. Dudley\n",
+	})
+
+	after := testRoot(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Lily =\n1. Rose\n2. Harry (Hal)\n\n? + ? =\n1. Tom\n\n? + ? =\n1. Ann\n2. Bob\n\nHarry + Ginny =\n1. Albus\n",
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ron\n2. Ginny\n",
+	})
+
+	var res []string
+
+	for _, change := range Diff(before, after) {
+		res = append(res, change.String())
+	}
+
+	expect := []string{
+		"family-removed Dursley/",
+		"person-removed Dursley/Dudley",
+		"person-removed Dursley/Petunia",
+		"person-removed Dursley/Vernon",
+		"person-added Potter/Albus",
+		"person-added Potter/Bob",
+		"person-added Potter/Ginny",
+		"alias-added Potter/Harry:  -> Hal",
+		"relation-removed Dursley/Vernon + Petunia =",
+		"relation-changed Potter/? + ? =#2: Ann -> Ann, Bob",
+		"relation-added Potter/Harry + Ginny =",
+		"children-reordered Potter/James + Lily =: Harry, Rose -> Rose, Harry",
+	}
+
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("\n%s\n!=\n%s", strings.Join(res, "\n"), strings.Join(expect, "\n"))
+	}
+}
+
+func TestDiffMoved(t *testing.T) {
+	before := testRoot(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n",
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ron\n",
+		"Evans.fml":   "Evans\n\nTom + Ann =\n1. Petunia\n",
+	})
+
+	after := testRoot(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Molly =\n1. Harry\n",
+		"Weasley.fml": "Weasley\n\nArthur + Lily =\n1. Ron\n\nTom Evans + Ann Evans =\n1. Petunia\n",
+		"Evans.fml":   "Evans\n\nTom + Ann =\n",
+	})
+
+	var res []string
+
+	for _, change := range Diff(before, after) {
+		if !strings.HasPrefix(change.String(), "relation-") {
+			res = append(res, change.String())
+		}
+	}
+
+	// Lily and Molly have no parents, so they are not paired
+	expect := []string{
+		"person-moved Weasley/Petunia: Evans -> Weasley",
+		"person-removed Potter/Lily",
+		"person-removed Weasley/Molly",
+		"person-added Potter/Molly",
+		"person-added Weasley/Lily",
+	}
+
+	if strings.Join(res, "\n") != strings.Join(expect, "\n") {
+		t.Errorf("\n%s\n!=\n%s", strings.Join(res, "\n"), strings.Join(expect, "\n"))
+	}
+}
+
+func TestDiffDuplicateNames(t *testing.T) {
+	before := testRoot(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny =\n1. Lily\n",
+	})
+
+	after := testRoot(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny =\n1. Lily (Luna)\n",
+	})
+
+	for range 10 {
+		list := Diff(before, after)
+
+		if len(list) != 1 || list[0].String() != "alias-added Potter/Lily:  -> Luna" {
+			t.Fatalf("changes: %v", list)
+		}
+	}
+}
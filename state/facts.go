@@ -0,0 +1,127 @@
+package state
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Facts are "key: value" lines of Markdown file, like front matter or "- **Born:** 1980-07-31"
+type Facts map[string]string
+
+var BirthFacts = []string{"born", "birth", "birthday", "birth date", "date of birth", "народився", "народилася", "родился", "родилась"}
+
+var factRegexp = regexp.MustCompile(`^[\s>*+-]*(?:\*\*|__)?([^:*_]+?)(?:\*\*|__)?\s*:\s*(?:\*\*|__)?\s*(.+?)\s*$`)
+var dateRegexp = regexp.MustCompile(`(\d{4})(?:[-./](\d{1,2})(?:[-./](\d{1,2}))?)?`)
+var dateReverseRegexp = regexp.MustCompile(`(\d{1,2})[-./](\d{1,2})[-./](\d{4})`)
+
+func GetMarkdownFacts(text string) Facts {
+	facts := make(Facts)
+
+	for _, line := range strings.Split(text, "\n") {
+		match := factRegexp.FindStringSubmatch(strings.TrimRight(line, "\r"))
+
+		if match == nil {
+			continue
+		}
+
+		key := strings.ToLower(strings.TrimSpace(match[1]))
+
+		if _, exist := facts[key]; !exist {
+			facts[key] = strings.Trim(match[2], "*_ ")
+		}
+	}
+
+	return facts
+}
+
+// GetMarkdownExcerpt returns first paragraph of Markdown text without front matter, headers and facts,
+// cut to size of runes
+func GetMarkdownExcerpt(text string, size int) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r", ""), "\n")
+
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				lines = lines[i+1:]
+				break
+			}
+		}
+	}
+
+	var paragraph []string
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			if len(paragraph) > 0 {
+				break
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") || factRegexp.MatchString(line) {
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+
+	excerpt := []rune(strings.Join(paragraph, " "))
+
+	if len(excerpt) > size {
+		return strings.TrimSpace(string(excerpt[:size])) + "…"
+	}
+
+	return string(excerpt)
+}
+
+// BirthDate returns date as number YYYYMMDD, zero when there is no date
+func (facts Facts) BirthDate() int {
+	for _, key := range BirthFacts {
+		if value, ok := facts[key]; ok {
+			return ParseDate(value)
+		}
+	}
+
+	return 0
+}
+
+// ParseDate returns date like 1980-07-31, 1980.07, 31.07.1980 or 1980 as number YYYYMMDD
+func ParseDate(text string) int {
+	var parts []string
+
+	if match := dateReverseRegexp.FindStringSubmatch(text); match != nil {
+		parts = []string{match[3], match[2], match[1]}
+	} else if match := dateRegexp.FindStringSubmatch(text); match != nil {
+		parts = match[1:]
+	} else {
+		return 0
+	}
+
+	date := 0
+
+	for _, part := range parts {
+		num, _ := strconv.Atoi(part)
+		date = date*100 + num
+	}
+
+	return date
+}
+
+// GetBirthDate returns birth date of member from its Markdown file
+func (member *Member) GetBirthDate() int {
+	if member.InfoUri == "" {
+		return 0
+	}
+
+	text, err := GetText(member.InfoUri)
+
+	if err != nil {
+		return 0
+	}
+
+	return GetMarkdownFacts(text).BirthDate()
+}
@@ -0,0 +1,47 @@
+package state
+
+import "testing"
+
+func TestGetMarkdownFacts(t *testing.T) {
+	text := "---\nborn: 1980-07-31\n---\n# Harry\n\n- **Place:** Godric's Hollow\n> __Died__: 2080\n"
+
+	facts := GetMarkdownFacts(text)
+
+	for key, value := range map[string]string{"born": "1980-07-31", "place": "Godric's Hollow", "died": "2080"} {
+		if facts[key] != value {
+			t.Errorf("fact %s: %q", key, facts[key])
+		}
+	}
+
+	if date := facts.BirthDate(); date != 19800731 {
+		t.Errorf("birth date: %d", date)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	list := map[string]int{
+		"1980-07-31": 19800731,
+		"31.07.1980": 19800731,
+		"1980.7":     19800700,
+		"about 1980": 19800000,
+		"unknown":    0,
+	}
+
+	for text, date := range list {
+		if res := ParseDate(text); res != date {
+			t.Errorf("%s: %d != %d", text, res, date)
+		}
+	}
+}
+
+func TestGetMarkdownExcerpt(t *testing.T) {
+	text := "---\nborn: 1980\n---\n# Harry\n\n- **Place:** Godric's Hollow\n\nThe boy\nwho lived.\n\nSecond paragraph"
+
+	if excerpt := GetMarkdownExcerpt(text, 100); excerpt != "The boy who lived." {
+		t.Errorf("excerpt: %q", excerpt)
+	}
+
+	if excerpt := GetMarkdownExcerpt(text, 7); excerpt != "The boy…" {
+		t.Errorf("short excerpt: %q", excerpt)
+	}
+}
@@ -33,7 +33,9 @@ func CreateFile(uri Uri, folder Uri) (file *File) {
 		Uri: uri,
 	}
 
-	path = s.TrimPrefix(path, folder)
+	folderPath, _ := UriToPath(folder)
+
+	path = s.TrimPrefix(path, folderPath)
 	path = s.TrimLeft(path, "/")
 
 	file.Path = s.Split(path, "/")
@@ -0,0 +1,230 @@
+package state
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+const (
+	GitIgnoreFileName = ".gitignore"
+	IgnoreFileName    = ".familymarkupignore"
+)
+
+// Ignore is a list of rules in .gitignore format
+type Ignore struct {
+	rules []*ignoreRule
+}
+
+type ignoreRule struct {
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+type Ignores map[Uri]*Ignore
+
+func CreateIgnore() *Ignore {
+	return &Ignore{}
+}
+
+// LoadIgnore creates rules for workspace folder from its .gitignore, .familymarkupignore and exclude globs
+func LoadIgnore(folder Uri, excludes ...[]string) *Ignore {
+	ignore := CreateIgnore()
+	ignore.Add("", ".git/")
+
+	folderPath, _ := UriToPath(folder)
+
+	_ = ignore.AddFiles("", folderPath)
+
+	for _, list := range excludes {
+		ignore.Add("", list...)
+	}
+
+	return ignore
+}
+
+// Add adds patterns relative to base folder (slash separated path relative to workspace folder)
+func (ignore *Ignore) Add(base string, patterns ...string) {
+	for _, pattern := range patterns {
+		rule := parseIgnoreRule(pattern)
+
+		if rule == nil {
+			continue
+		}
+
+		rule.base = base
+		ignore.rules = append(ignore.rules, rule)
+	}
+}
+
+// AddFiles adds patterns from .gitignore and .familymarkupignore files of dir
+func (ignore *Ignore) AddFiles(base string, dir string) (err error) {
+	for _, name := range []string{GitIgnoreFileName, IgnoreFileName} {
+		bytes, e := os.ReadFile(filepath.Join(dir, name))
+
+		if errors.Is(e, fs.ErrNotExist) {
+			continue
+		}
+
+		if e != nil {
+			err = e
+			continue
+		}
+
+		ignore.Add(base, strings.Split(string(bytes), "\n")...)
+	}
+
+	return
+}
+
+// Match checks slash separated path relative to workspace folder including all its parent folders
+func (ignore *Ignore) Match(path string, isDir bool) bool {
+	parts := strings.Split(path, "/")
+
+	for i := 1; i < len(parts); i++ {
+		if ignore.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return ignore.match(path, isDir)
+}
+
+func (ignore *Ignore) match(path string, isDir bool) (ignored bool) {
+	if ignore == nil {
+		return
+	}
+
+	for _, rule := range ignore.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+
+		rel := path
+
+		if rule.base != "" {
+			if !strings.HasPrefix(path, rule.base+"/") {
+				continue
+			}
+
+			rel = path[len(rule.base)+1:]
+		}
+
+		if rule.re.MatchString(rel) {
+			ignored = !rule.negate
+		}
+	}
+
+	return
+}
+
+func parseIgnoreRule(pattern string) (rule *ignoreRule) {
+	pattern = strings.TrimRight(pattern, " \t\r")
+
+	if pattern == "" || strings.HasPrefix(pattern, "#") {
+		return
+	}
+
+	rule = &ignoreRule{}
+
+	if strings.HasPrefix(pattern, "!") {
+		rule.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\!`) || strings.HasPrefix(pattern, `\#`) {
+		pattern = pattern[1:]
+	}
+
+	if strings.HasSuffix(pattern, "/") {
+		rule.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+
+	if pattern == "" {
+		return nil
+	}
+
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var re strings.Builder
+
+	re.WriteString("^")
+
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+
+	chars := []rune(pattern)
+	count := len(chars)
+
+	for i := 0; i < count; i++ {
+		c := chars[i]
+
+		switch c {
+		case '*':
+			if i+1 < count && chars[i+1] == '*' {
+				i++
+
+				if i+1 < count && chars[i+1] == '/' {
+					i++
+					re.WriteString("(?:.*/)?")
+				} else {
+					re.WriteString(".*")
+				}
+
+				continue
+			}
+
+			re.WriteString("[^/]*")
+
+		case '?':
+			re.WriteString("[^/]")
+
+		case '[':
+			end := strings.IndexRune(string(chars[i+1:]), ']')
+
+			if end == -1 {
+				re.WriteString(`\[`)
+				continue
+			}
+
+			class := []rune(string(chars[i+1:])[:end])
+
+			if len(class) > 0 && class[0] == '!' {
+				class[0] = '^'
+			}
+
+			re.WriteString("[" + strings.ReplaceAll(string(class), `\`, `\\`) + "]")
+			i += len(class) + 1
+
+		case '\\':
+			if i+1 < count {
+				i++
+				re.WriteString(regexp.QuoteMeta(string(chars[i])))
+			}
+
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	re.WriteString("$")
+
+	compiled, err := regexp.Compile(re.String())
+
+	if err != nil {
+		return nil
+	}
+
+	rule.re = compiled
+
+	return
+}
@@ -0,0 +1,99 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/redexp/familymarkup-lsp/utils"
+)
+
+func TestIgnoreMatch(t *testing.T) {
+	ignore := CreateIgnore()
+	ignore.Add("",
+		"# comment",
+		"",
+		"*.bak",
+		"archive/old/",
+		"/drafts",
+		"docs/**/tmp.fml",
+		"!keep.bak",
+		"export?.fml",
+		"[Cc]opy*",
+	)
+	ignore.Add("nested", "local.fml")
+
+	list := []struct {
+		Path    string
+		IsDir   bool
+		Ignored bool
+	}{
+		{"Potter.fml", false, false},
+		{"Potter.fml.bak", false, true},
+		{"deep/Potter.bak", false, true},
+		{"keep.bak", false, false},
+		{"archive/old", true, true},
+		{"archive/old/Potter.fml", false, true},
+		{"archive/Potter.fml", false, false},
+		{"archive/old", false, false},
+		{"drafts/Potter.fml", false, true},
+		{"sub/drafts/Potter.fml", false, false},
+		{"docs/tmp.fml", false, true},
+		{"docs/a/b/tmp.fml", false, true},
+		{"export1.fml", false, true},
+		{"export12.fml", false, false},
+		{"Copy of Potter.fml", false, true},
+		{"copy.fml", false, true},
+		{"nested/local.fml", false, true},
+		{"local.fml", false, false},
+	}
+
+	for i, item := range list {
+		if ignore.Match(item.Path, item.IsDir) != item.Ignored {
+			t.Errorf("%d - %s: expect ignored %v", i+1, item.Path, item.Ignored)
+		}
+	}
+}
+
+func TestWalkFilesIgnore(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":                  "*.bak\n",
+		".familymarkupignore":         "drafts/\n",
+		"Potter.fml":                  "",
+		"Potter.fml.bak":              "",
+		"drafts/Weasley.fml":          "",
+		"archive/.gitignore":          "old/\n",
+		"archive/old/Potter.fml":      "",
+		"archive/Weasley.fml":         "",
+		"archive/Weasley/Ron.md":      "",
+		"archive/Weasley/Ron.md.copy": "",
+	}
+
+	for name, text := range files {
+		path := filepath.Join(dir, name)
+		_ = os.MkdirAll(filepath.Dir(path), 0755)
+
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := make(map[string]bool)
+
+	err := WalkFiles(dir, []string{"fml", "md"}, LoadIgnore(dir, []string{"*.md"}), func(uri string, _ string) error {
+		path, _ := UriToPath(uri)
+		rel, _ := filepath.Rel(dir, path)
+		found[filepath.ToSlash(rel)] = true
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(found) != 2 || !found["Potter.fml"] || !found["archive/Weasley.fml"] {
+		t.Errorf("found: %v", found)
+	}
+}
@@ -0,0 +1,203 @@
+package state
+
+import (
+	"iter"
+
+	fm "github.com/redexp/familymarkup-parser"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+)
+
+// ParentsIter iterates sources of relation where member was born
+func (root *Root) ParentsIter(member *Member) iter.Seq[*Member] {
+	return func(yield func(*Member) bool) {
+		origin := member.GetOrigin()
+		person := origin.Person
+
+		if !person.IsChild {
+			return
+		}
+
+		for _, p := range person.Relation.Sources.Persons {
+			if p.Name == nil {
+				continue
+			}
+
+			parent := root.GetMemberByToken(origin.Family.Uri, p.Name)
+
+			if parent != nil && !yield(parent.GetOrigin()) {
+				return
+			}
+		}
+	}
+}
+
+// PartnersIter iterates other sources of all relations where member is a source
+func (root *Root) PartnersIter(member *Member) iter.Seq[*Member] {
+	return func(yield func(*Member) bool) {
+		origin := member.GetOrigin()
+		uniq := map[*Member]bool{
+			origin: true,
+		}
+
+		for ref, uri := range root.RefsIter() {
+			p := ref.Person
+
+			if ref.Member == nil || p == nil || p.Side != fm.SideSources || ref.Member.GetOrigin() != origin {
+				continue
+			}
+
+			for _, s := range p.Relation.Sources.Persons {
+				if s == p || s.Name == nil {
+					continue
+				}
+
+				partner := root.GetMemberByToken(uri, s.Name)
+
+				if partner == nil || uniq[partner.GetOrigin()] {
+					continue
+				}
+
+				uniq[partner.GetOrigin()] = true
+
+				if !yield(partner.GetOrigin()) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// SourceRelationsIter iterates persons of member in sources of all its relations in all families
+func (root *Root) SourceRelationsIter(member *Member) iter.Seq2[*fm.Person, Uri] {
+	return func(yield func(*fm.Person, Uri) bool) {
+		origin := member.GetOrigin()
+		uniq := make(map[*fm.Relation]bool)
+
+		for ref, uri := range root.RefsIter() {
+			p := ref.Person
+
+			if ref.Member == nil || p == nil || p.Side != fm.SideSources || uniq[p.Relation] || ref.Member.GetOrigin() != origin {
+				continue
+			}
+
+			uniq[p.Relation] = true
+
+			if !yield(p, uri) {
+				return
+			}
+		}
+	}
+}
+
+// ChildrenIter iterates targets of all relations where member is a source
+func (root *Root) ChildrenIter(member *Member) iter.Seq[*Member] {
+	return func(yield func(*Member) bool) {
+		uniq := make(map[*Member]bool)
+
+		for p, uri := range root.SourceRelationsIter(member) {
+			if p.Relation.Targets == nil {
+				continue
+			}
+
+			for _, t := range p.Relation.Targets.Persons {
+				if t.Name == nil {
+					continue
+				}
+
+				child := root.GetMemberByToken(uri, t.Name)
+
+				if child == nil || uniq[child.GetOrigin()] {
+					continue
+				}
+
+				uniq[child.GetOrigin()] = true
+
+				if !yield(child.GetOrigin()) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// DescendantsIter iterates children of member and their descendants across all families
+func (root *Root) DescendantsIter(member *Member) iter.Seq[*Member] {
+	return func(yield func(*Member) bool) {
+		origin := member.GetOrigin()
+		visited := map[*Member]bool{
+			origin: true,
+		}
+
+		queue := []*Member{origin}
+
+		for len(queue) > 0 {
+			mem := queue[0]
+			queue = queue[1:]
+
+			for child := range root.ChildrenIter(mem) {
+				if visited[child] {
+					continue
+				}
+
+				visited[child] = true
+
+				if !yield(child) {
+					return
+				}
+
+				queue = append(queue, child)
+			}
+		}
+	}
+}
+
+// Kinship returns the closest common ancestor of two members
+// and number of generations from each of them to the ancestor,
+// from ancestors with the same distances the first one in the document is returned
+func (root *Root) Kinship(a *Member, b *Member) (ancestor *Member, da int, db int) {
+	ancestorsA := root.ancestors(a)
+	ancestorsB := root.ancestors(b)
+
+	for mem, distA := range ancestorsA {
+		distB, ok := ancestorsB[mem]
+
+		if !ok {
+			continue
+		}
+
+		if ancestor == nil || distA+distB < da+db || (distA+distB == da+db && (distA < da || (distA == da && CompareMembers(mem, ancestor) < 0))) {
+			ancestor = mem
+			da = distA
+			db = distB
+		}
+	}
+
+	return
+}
+
+// ancestors returns member itself and all its ancestors with number of generations to them
+func (root *Root) ancestors(member *Member) map[*Member]int {
+	origin := member.GetOrigin()
+	res := map[*Member]int{
+		origin: 0,
+	}
+
+	queue := []*Member{origin}
+
+	for len(queue) > 0 {
+		mem := queue[0]
+		queue = queue[1:]
+
+		for parent := range root.ParentsIter(mem) {
+			if _, ok := res[parent]; ok {
+				continue
+			}
+
+			res[parent] = res[mem] + 1
+			queue = append(queue, parent)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestKinship(t *testing.T) {
+	root := testRoot(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n2. Rose\n\nHarry + Ginny =\n1. Albus\n",
+	})
+
+	member := func(name string) *Member {
+		_, mem := root.FindMember("Potter", name)
+
+		if mem == nil {
+			t.Fatalf("member %s not found", name)
+		}
+
+		return mem
+	}
+
+	for range 20 {
+		ancestor, da, db := root.Kinship(member("Harry"), member("Rose"))
+
+		if ancestor != member("James") || da != 1 || db != 1 {
+			t.Fatalf("siblings: %v %d %d", ancestor, da, db)
+		}
+	}
+
+	ancestor, da, db := root.Kinship(member("Albus"), member("Rose"))
+
+	if ancestor != member("James") || da != 2 || db != 1 {
+		t.Errorf("nephew: %v %d %d", ancestor, da, db)
+	}
+
+	ancestor, da, db = root.Kinship(member("Albus"), member("Harry"))
+
+	if ancestor != member("Harry") || da != 1 || db != 0 {
+		t.Errorf("parent: %v %d %d", ancestor, da, db)
+	}
+}
+
+// testRoot writes files to temp folder and loads it
+func testRoot(t *testing.T, files map[string]string) *Root {
+	dir := t.TempDir()
+
+	for name, text := range files {
+		path := filepath.Join(dir, name)
+
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+
+		if err == nil {
+			err = os.WriteFile(path, []byte(text), 0644)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	root, err := LoadSnapshot(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
@@ -1,8 +1,10 @@
 package state
 
 import (
+	"cmp"
 	"iter"
 	"slices"
+	"strings"
 
 	fm "github.com/redexp/familymarkup-parser"
 
@@ -109,6 +111,24 @@ func (member *Member) GetAllRefsIter() iter.Seq2[*Ref, Uri] {
 	}
 }
 
+// GetOrigin returns member of the family where person was born
+func (member *Member) GetOrigin() *Member {
+	if member.Origin != nil {
+		return member.Origin
+	}
+
+	return member
+}
+
+// GetSurname returns surname of family where person was born
+func (member *Member) GetSurname() string {
+	if member.Surname != "" {
+		return member.Surname
+	}
+
+	return member.Family.Name
+}
+
 func (member *Member) HasRef() bool {
 	for ref := range member.GetRefsIter() {
 		if ref.Person != member.Person {
@@ -118,3 +138,12 @@ func (member *Member) HasRef() bool {
 
 	return false
 }
+
+// CompareMembers compares members by file and position of the name in the document
+func CompareMembers(a *Member, b *Member) int {
+	return cmp.Or(
+		strings.Compare(a.Family.Uri, b.Family.Uri),
+		a.Person.Name.Line-b.Person.Name.Line,
+		a.Person.Name.Char-b.Person.Name.Char,
+	)
+}
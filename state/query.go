@@ -0,0 +1,373 @@
+package state
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	fm "github.com/redexp/familymarkup-parser"
+
+	. "github.com/redexp/familymarkup-lsp/types"
+)
+
+// Query is a structural question about the family graph like
+// "descendants(Potter/Harry) where surname != Potter", "persons without parents" or "children of ? + ?"
+type Query struct {
+	// "persons", "children of" or name of function like "descendants"
+	Source string
+	// Filter of "persons" like "without parents"
+	Filter string
+	// Person argument of function, "Surname/Name", "Name Surname" or unique "Name"
+	Person string
+	// Persons of "children of", "?" for unknown person
+	Sources    []string
+	Conditions []QueryCondition
+}
+
+type QueryCondition struct {
+	Field string
+	Op    string
+	Value string
+}
+
+const (
+	QueryPersons    = "persons"
+	QueryChildrenOf = "children of"
+)
+
+var QueryFunctions = []string{"descendants", "ancestors", "children", "parents", "partners"}
+
+var QueryFilters = []string{"without parents", "without children", "without partners", "with biography", "with biography missing", "without biography"}
+
+var QueryFields = []string{"name", "surname"}
+
+var queryWhereRegexp = regexp.MustCompile(`(?i)\s+where\s+`)
+var queryAndRegexp = regexp.MustCompile(`(?i)\s+and\s+`)
+var queryConditionRegexp = regexp.MustCompile(`^(\w+)\s*(!=|=|~)\s*(.+)$`)
+var queryFunctionRegexp = regexp.MustCompile(`^(\w+)\s*\(\s*(.+?)\s*\)$`)
+var queryPersonsRegexp = regexp.MustCompile(`(?i)^persons(?:\s+(.+))?$`)
+var queryChildrenOfRegexp = regexp.MustCompile(`(?i)^children\s+of\s+(.+)$`)
+
+func ParseQuery(text string) (query *Query, err error) {
+	parts := queryWhereRegexp.Split(strings.TrimSpace(text), 2)
+	selection := parts[0]
+	query = &Query{}
+
+	if match := queryPersonsRegexp.FindStringSubmatch(selection); match != nil {
+		query.Source = QueryPersons
+		query.Filter = strings.ToLower(strings.Join(strings.Fields(match[1]), " "))
+
+		if query.Filter != "" && !slices.Contains(QueryFilters, query.Filter) {
+			return nil, fmt.Errorf("unknown filter %q, expected one of: %s", match[1], strings.Join(QueryFilters, ", "))
+		}
+	} else if match := queryChildrenOfRegexp.FindStringSubmatch(selection); match != nil {
+		query.Source = QueryChildrenOf
+
+		for _, name := range strings.Split(match[1], "+") {
+			name = strings.Join(strings.Fields(name), " ")
+
+			if name == "" {
+				return nil, fmt.Errorf("empty person in %q", selection)
+			}
+
+			query.Sources = append(query.Sources, name)
+		}
+	} else if match := queryFunctionRegexp.FindStringSubmatch(selection); match != nil {
+		query.Source = strings.ToLower(match[1])
+		query.Person = match[2]
+
+		if !slices.Contains(QueryFunctions, query.Source) {
+			return nil, fmt.Errorf("unknown function %q, expected one of: %s", match[1], strings.Join(QueryFunctions, ", "))
+		}
+	} else {
+		return nil, fmt.Errorf("unknown query %q", selection)
+	}
+
+	if len(parts) == 1 {
+		return
+	}
+
+	for _, item := range queryAndRegexp.Split(parts[1], -1) {
+		match := queryConditionRegexp.FindStringSubmatch(strings.TrimSpace(item))
+
+		if match == nil {
+			return nil, fmt.Errorf("wrong condition %q, expected like: surname != Potter", item)
+		}
+
+		field := strings.ToLower(match[1])
+
+		if !slices.Contains(QueryFields, field) {
+			return nil, fmt.Errorf("unknown field %q, expected one of: %s", match[1], strings.Join(QueryFields, ", "))
+		}
+
+		query.Conditions = append(query.Conditions, QueryCondition{
+			Field: field,
+			Op:    match[2],
+			Value: strings.TrimSpace(match[3]),
+		})
+	}
+
+	return
+}
+
+// RunQuery returns members (persons where they were born) selected by query sorted by file and position
+func (root *Root) RunQuery(query *Query) (list []*Member, err error) {
+	uniq := make(map[*Member]bool)
+
+	add := func(mem *Member) {
+		mem = mem.GetOrigin()
+
+		if uniq[mem] || !query.Match(mem) {
+			return
+		}
+
+		uniq[mem] = true
+		list = append(list, mem)
+	}
+
+	switch query.Source {
+	case QueryPersons:
+		filter := root.queryFilter(query.Filter)
+
+		for mem := range root.MembersIter() {
+			if mem.Origin == nil && filter(mem) {
+				add(mem)
+			}
+		}
+
+	case QueryChildrenOf:
+		for f := range root.FamilyIter() {
+			for _, rel := range f.Node.Relations {
+				if rel.Targets == nil || !root.matchSources(f.Uri, rel.Sources.Persons, query.Sources) {
+					continue
+				}
+
+				for _, p := range rel.Targets.Persons {
+					if p.Name == nil {
+						continue
+					}
+
+					if mem := root.GetMemberByToken(f.Uri, p.Name); mem != nil {
+						add(mem)
+					}
+				}
+			}
+		}
+
+	default:
+		mem, err := root.findQueryPerson(query.Person)
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch query.Source {
+		case "descendants":
+			for m := range root.DescendantsIter(mem) {
+				add(m)
+			}
+
+		case "ancestors":
+			for m, dist := range root.ancestors(mem) {
+				if dist > 0 {
+					add(m)
+				}
+			}
+
+		case "children":
+			for m := range root.ChildrenIter(mem) {
+				add(m)
+			}
+
+		case "parents":
+			for m := range root.ParentsIter(mem) {
+				add(m)
+			}
+
+		case "partners":
+			for m := range root.PartnersIter(mem) {
+				add(m)
+			}
+		}
+	}
+
+	slices.SortFunc(list, CompareMembers)
+
+	return
+}
+
+// Match returns true if member matches all conditions of query
+func (query *Query) Match(mem *Member) bool {
+	for _, c := range query.Conditions {
+		var eq bool
+
+		switch c.Field {
+		case "name":
+			for name := range mem.NamesIter() {
+				if matchQueryValue(name, c) {
+					eq = true
+					break
+				}
+			}
+
+		case "surname":
+			eq = matchQueryValue(mem.GetSurname(), c)
+		}
+
+		if eq == (c.Op == "!=") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchQueryValue compares value case-insensitive, operator "~" compares with transliteration, so Olena ~ Олена
+func matchQueryValue(value string, c QueryCondition) bool {
+	if c.Op == "~" {
+		return NameKey(value) == NameKey(c.Value)
+	}
+
+	return strings.EqualFold(value, c.Value)
+}
+
+func (root *Root) queryFilter(filter string) func(*Member) bool {
+	switch filter {
+	case "without parents":
+		return func(mem *Member) bool {
+			for range root.ParentsIter(mem) {
+				return false
+			}
+
+			return true
+		}
+
+	case "without children", "without partners":
+		found := make(map[*Member]bool)
+
+		for ref := range root.RefsIter() {
+			p := ref.Person
+
+			if ref.Member == nil || p == nil || p.Side != fm.SideSources {
+				continue
+			}
+
+			if filter == "without children" && p.Relation.Targets != nil && len(p.Relation.Targets.Persons) > 0 {
+				found[ref.Member.GetOrigin()] = true
+			}
+
+			if filter == "without partners" && len(p.Relation.Sources.Persons) > 1 {
+				found[ref.Member.GetOrigin()] = true
+			}
+		}
+
+		return func(mem *Member) bool {
+			return !found[mem]
+		}
+
+	case "with biography":
+		return func(mem *Member) bool {
+			return mem.InfoUri != ""
+		}
+
+	case "with biography missing", "without biography":
+		return func(mem *Member) bool {
+			return mem.InfoUri == ""
+		}
+	}
+
+	return func(*Member) bool {
+		return true
+	}
+}
+
+// findQueryPerson returns member by "Surname/Name", "Name Surname" or "Name" when the name is unique
+func (root *Root) findQueryPerson(text string) (mem *Member, err error) {
+	var surname, name string
+
+	if before, after, ok := strings.Cut(text, "/"); ok {
+		surname, name = before, after
+	} else if before, after, ok := strings.Cut(text, " "); ok {
+		name, surname = before, after
+	} else {
+		for m := range root.MembersIter() {
+			if m.Origin != nil || !m.HasName(text) {
+				continue
+			}
+
+			if mem != nil {
+				return nil, fmt.Errorf("person %q is not unique, add surname", text)
+			}
+
+			mem = m
+		}
+	}
+
+	if surname != "" {
+		_, mem = root.FindMember(strings.TrimSpace(surname), strings.TrimSpace(name))
+	}
+
+	if mem == nil {
+		return nil, fmt.Errorf("person %q not found", text)
+	}
+
+	return
+}
+
+// matchSources returns true if each person of relation sources matches one of names,
+// "?" matches unknown person and names without surname match any surname
+func (root *Root) matchSources(uri Uri, persons []*fm.Person, names []string) bool {
+	if len(persons) != len(names) {
+		return false
+	}
+
+	used := make([]bool, len(names))
+
+	for _, p := range persons {
+		found := false
+
+		for i, name := range names {
+			if used[i] || !root.matchSource(uri, p, name) {
+				continue
+			}
+
+			used[i] = true
+			found = true
+			break
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (root *Root) matchSource(uri Uri, p *fm.Person, text string) bool {
+	if p.Unknown != nil || p.Name == nil {
+		return text == "?"
+	}
+
+	mem := root.GetMemberByToken(uri, p.Name)
+
+	if mem == nil {
+		return false
+	}
+
+	mem = mem.GetOrigin()
+	name, surname := text, ""
+
+	if before, after, ok := strings.Cut(text, "/"); ok {
+		surname, name = before, after
+	} else if before, after, ok := strings.Cut(text, " "); ok {
+		name, surname = before, after
+	}
+
+	if surname != "" && !strings.EqualFold(surname, mem.GetSurname()) {
+		return false
+	}
+
+	return mem.HasName(name)
+}
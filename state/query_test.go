@@ -0,0 +1,101 @@
+package state
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	q, err := ParseQuery("descendants(Potter/Harry) where surname != Potter and name ~ Олена")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if q.Source != "descendants" || q.Person != "Potter/Harry" || len(q.Conditions) != 2 || q.Conditions[0] != (QueryCondition{"surname", "!=", "Potter"}) || q.Conditions[1].Op != "~" {
+		t.Errorf("query: %+v", q)
+	}
+
+	q, err = ParseQuery("persons  with Biography missing")
+
+	if err != nil || q.Source != QueryPersons || q.Filter != "with biography missing" {
+		t.Errorf("persons: %+v %v", q, err)
+	}
+
+	q, err = ParseQuery("children of ? + Harry Potter")
+
+	if err != nil || q.Source != QueryChildrenOf || !slices.Equal(q.Sources, []string{"?", "Harry Potter"}) {
+		t.Errorf("children of: %+v %v", q, err)
+	}
+
+	for _, text := range []string{"people", "persons with cats", "cousins(Potter/Harry)", "persons where age > 10", "children of ? +"} {
+		if _, err = ParseQuery(text); err == nil {
+			t.Errorf("expected error of %q", text)
+		}
+	}
+}
+
+func TestFindQueryPerson(t *testing.T) {
+	root := testRoot(t, map[string]string{
+		"Potter.fml": "Potter\n\nJames + Lily =\n1. Harry\n",
+		"Evans.fml":  "Evans\n\nTom + Ann =\n1. Lily\n",
+	})
+
+	for _, text := range []string{"Harry", "Potter/Harry", "Harry Potter"} {
+		mem, err := root.findQueryPerson(text)
+
+		if err != nil || mem.Name != "Harry" {
+			t.Errorf("%s: %v %v", text, mem, err)
+		}
+	}
+
+	for _, text := range []string{"Lily", "Ron", "Weasley/Ron"} {
+		if _, err := root.findQueryPerson(text); err == nil {
+			t.Errorf("expected error of %q", text)
+		}
+	}
+}
+
+func TestRunQuery(t *testing.T) {
+	root := testRoot(t, map[string]string{
+		"Potter.fml":  "Potter\n\nJames + Lily =\n1. Harry\n\nHarry + Ginny Weasley =\n1. Albus\n2. Lily\n\n? + Harry =\n1. Tom\n",
+		"Weasley.fml": "Weasley\n\nArthur + Molly =\n1. Ginny\n2. Ron\n",
+	})
+
+	list := []struct {
+		query  string
+		expect []string
+	}{
+		{"descendants(Arthur Weasley) where surname != Weasley", []string{"Albus", "Lily"}},
+		{"descendants(Potter/Harry) where name != Tom", []string{"Albus", "Lily"}},
+		{"persons without parents", []string{"James", "Lily", "Arthur", "Molly"}},
+		{"children of ? + Harry", []string{"Tom"}},
+		{"children of ? + ?", nil},
+		{"children of Ginny + Harry Potter", []string{"Albus", "Lily"}},
+	}
+
+	for _, item := range list {
+		query, err := ParseQuery(item.query)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := root.RunQuery(query)
+
+		if err != nil {
+			t.Errorf("%s: %s", item.query, err)
+			continue
+		}
+
+		names := make([]string, 0, len(res))
+
+		for _, mem := range res {
+			names = append(names, mem.Name)
+		}
+
+		if !slices.Equal(names, item.expect) {
+			t.Errorf("%s: %v", item.query, names)
+		}
+	}
+}
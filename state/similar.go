@@ -0,0 +1,84 @@
+package state
+
+// SimilarMembers are members which are probably the same person, like two copies
+// of a great-grandfather entered independently in different families
+type SimilarMembers map[*Member][]*Member
+
+// FindSimilarMembers returns members with the same or transliterated name and surname
+// which have at least one the same parent or partner, result is cached until next UpdateDirty
+func (root *Root) FindSimilarMembers() SimilarMembers {
+	if root.Similar != nil {
+		return root.Similar
+	}
+
+	groups := make(map[string][]*Member)
+
+	for mem := range root.MembersIter() {
+		if mem.Origin != nil {
+			continue
+		}
+
+		key := memberKey(mem)
+		groups[key] = append(groups[key], mem)
+	}
+
+	res := make(SimilarMembers)
+
+	for _, list := range groups {
+		if len(list) < 2 {
+			continue
+		}
+
+		relatives := make([]map[string]bool, len(list))
+
+		for i, mem := range list {
+			relatives[i] = root.relativeKeys(mem)
+		}
+
+		for i, a := range list {
+			for j := i + 1; j < len(list); j++ {
+				b := list[j]
+
+				// children with the same name in one relation are reported as duplicate names
+				if a.Person.Relation == b.Person.Relation || !hasCommonKey(relatives[i], relatives[j]) {
+					continue
+				}
+
+				res[a] = append(res[a], b)
+				res[b] = append(res[b], a)
+			}
+		}
+	}
+
+	root.Similar = res
+
+	return res
+}
+
+func (root *Root) relativeKeys(mem *Member) map[string]bool {
+	keys := make(map[string]bool)
+
+	for parent := range root.ParentsIter(mem) {
+		keys[memberKey(parent)] = true
+	}
+
+	for partner := range root.PartnersIter(mem) {
+		keys[memberKey(partner)] = true
+	}
+
+	return keys
+}
+
+func memberKey(mem *Member) string {
+	return NameKey(mem.Name) + " " + NameKey(mem.GetSurname())
+}
+
+func hasCommonKey(a map[string]bool, b map[string]bool) bool {
+	for key := range a {
+		if b[key] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -14,6 +14,9 @@ import (
 
 type Root struct {
 	Folders      UriSet
+	Configs      Configs
+	Ignores      Ignores
+	Exclude      []string
 	Docs         Docs
 	Families     Families
 	Duplicates   Duplicates
@@ -23,6 +26,7 @@ type Root struct {
 	DirtyUris    DirtyUris
 	Labels       map[Uri][]string
 	Listeners    Listeners
+	Similar      SimilarMembers // cache of FindSimilarMembers, reset on every update
 
 	UpdateLock sync.Mutex
 }
@@ -30,6 +34,8 @@ type Root struct {
 func CreateRoot() *Root {
 	return &Root{
 		Folders:      make(UriSet),
+		Configs:      make(Configs),
+		Ignores:      make(Ignores),
 		Docs:         make(Docs),
 		Families:     make(Families),
 		Duplicates:   make(Duplicates),
@@ -41,11 +47,22 @@ func CreateRoot() *Root {
 	}
 }
 
-func (root *Root) SetFolders(folders []Uri) {
+func (root *Root) SetFolders(folders []Uri) (err error) {
 	root.Folders = make(UriSet)
+	root.Configs = make(Configs)
+	root.Ignores = make(Ignores)
 
 	for _, uri := range folders {
 		root.Folders.Set(uri)
+
+		config, e := LoadConfig(uri)
+
+		if e != nil {
+			err = e
+		}
+
+		root.Configs[uri] = config
+		root.Ignores[uri] = LoadIgnore(uri, config.Ignore, root.Exclude)
 	}
 
 	type TextTree struct {
@@ -57,8 +74,10 @@ func (root *Root) SetFolders(folders []Uri) {
 
 	go func() {
 		for uri := range root.Folders {
-			_ = WalkFiles(uri, AllExt, func(uri Uri, ext string) error {
-				if slices.Contains(MarkdownExt, ext) {
+			config := root.Configs[uri]
+
+			_ = WalkFiles(uri, config.AllExt(), root.Ignores[uri], func(uri Uri, ext string) error {
+				if slices.Contains(config.Extensions.Markdown, ext) {
 					textTrees <- TextTree{
 						Uri: uri,
 					}
@@ -84,9 +103,46 @@ func (root *Root) SetFolders(folders []Uri) {
 		close(textTrees)
 	}()
 
+	found := make(UriSet)
+
 	for item := range textTrees {
+		found.Set(item.Uri)
+
+		if root.DirtyUris.Has(item.Uri) {
+			continue
+		}
+
+		if doc, ok := root.Docs[item.Uri]; ok && (doc.Open || doc.Text == item.Text) {
+			continue
+		}
+
 		root.DirtyUris.SetText(item.Uri, UriCreate, item.Text)
 	}
+
+	// on reindex remove files which are ignored now
+	lost := func(uri Uri) bool {
+		return uri != "" && !found.Has(uri) && root.FindFolder(uri) != ""
+	}
+
+	for uri, doc := range root.Docs {
+		if lost(uri) && (!doc.Open || root.IsIgnored(uri)) {
+			root.DirtyUris.Set(uri, UriDelete)
+		}
+	}
+
+	for uri := range root.UnknownFiles {
+		if lost(uri) {
+			root.DirtyUris.Set(uri, UriDelete)
+		}
+	}
+
+	for mem := range root.MembersIter() {
+		if lost(mem.InfoUri) {
+			root.DirtyUris.Set(mem.InfoUri, UriDelete)
+		}
+	}
+
+	return
 }
 
 func (root *Root) Update(doc *Doc) {
@@ -290,6 +346,7 @@ func (root *Root) UpdateDirty() (err error) {
 
 	uris := root.DirtyUris
 	root.DirtyUris = make(DirtyUris)
+	root.Similar = nil
 	root.UnknownRefs = slices.DeleteFunc(root.UnknownRefs, func(ref *Ref) bool {
 		return uris.Has(ref.Uri)
 	})
@@ -309,7 +366,7 @@ func (root *Root) UpdateDirty() (err error) {
 		delete(root.Labels, uri)
 		delete(root.NodeRefs, uri)
 
-		if !IsMarkdownUri(uri) {
+		if !root.IsMarkdownUri(uri) {
 			continue
 		}
 
@@ -825,6 +882,60 @@ func (root *Root) FindFolder(uri Uri) Uri {
 	return ""
 }
 
+func (root *Root) GetConfig(uri Uri) *Config {
+	config, ok := root.Configs[root.FindFolder(uri)]
+
+	if !ok {
+		return CreateConfig()
+	}
+
+	return config
+}
+
+func (root *Root) IsFamilyUri(uri Uri) bool {
+	return root.GetConfig(uri).IsFamilyUri(uri)
+}
+
+func (root *Root) IsMarkdownUri(uri Uri) bool {
+	return root.GetConfig(uri).IsMarkdownUri(uri)
+}
+
+func (root *Root) IsIgnored(uri Uri) bool {
+	folder := root.FindFolder(uri)
+	ignore, ok := root.Ignores[folder]
+
+	if !ok {
+		return false
+	}
+
+	return ignore.Match(relativePath(folder, uri), false)
+}
+
+func (root *Root) AllExt() []string {
+	list := slices.Clone(AllExt)
+
+	for _, config := range root.Configs {
+		for _, ext := range config.AllExt() {
+			if !slices.Contains(list, ext) {
+				list = append(list, ext)
+			}
+		}
+	}
+
+	return list
+}
+
+func (root *Root) InfoFolder(uri Uri) Uri {
+	folder := root.FindFolder(uri)
+	info := root.GetConfig(uri).Info.Folder
+
+	if info == "" || info == "." {
+		return folder
+	}
+
+	return toFolderUri(folder) + info
+}
+
 func (root *Root) AddUnknownRef(ref *Ref) {
 	if ref.Type == RefTypeSurname {
 		for _, u := range root.UnknownRefs {
@@ -838,7 +949,13 @@ func (root *Root) AddUnknownRef(ref *Ref) {
 }
 
 func (root *Root) AddUnknownFile(uri Uri) {
-	file := CreateFile(uri, root.FindFolder(uri))
+	folder := root.InfoFolder(uri)
+
+	if !strings.HasPrefix(uri, folder) {
+		return
+	}
+
+	file := CreateFile(uri, folder)
 
 	root.UnknownFiles[uri] = file
 }
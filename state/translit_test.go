@@ -0,0 +1,39 @@
+package state
+
+import "testing"
+
+func TestNameKey(t *testing.T) {
+	list := [][2]string{
+		{"Олена", "Olena"},
+		{"Олександр", "Oleksandr"},
+		{"Євген", "Yevhen"},
+		{"Юрій", "Yurii"},
+		{"Ярослава", "Jaroslava"},
+		{"Zoë", "Zoe"},
+		{"Мар'яна", "Mariana"},
+	}
+
+	for _, pair := range list {
+		a, b := NameKey(pair[0]), NameKey(pair[1])
+
+		if a != b {
+			t.Errorf("%s (%s) != %s (%s)", pair[0], a, pair[1], b)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	list := map[[2]string]int{
+		{"harry", "harry"}:   0,
+		{"harry", "hary"}:    1,
+		{"harry", "garry"}:   1,
+		{"oleksandr", "ale"}: 7,
+		{"", "ron"}:          3,
+	}
+
+	for pair, dist := range list {
+		if d := EditDistance(pair[0], pair[1]); d != dist {
+			t.Errorf("%s %s: %d", pair[0], pair[1], d)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"io/fs"
 	"path/filepath"
 	"slices"
+	"strings"
 
 	. "github.com/redexp/familymarkup-lsp/types"
 	. "github.com/redexp/familymarkup-lsp/utils"
@@ -51,7 +52,7 @@ func IsEqNames(a string, b string) bool {
 	return compareNames([]rune(a), []rune(b)) <= 2
 }
 
-func WalkFiles(uri Uri, extensions []string, cb func(Uri, string) error) (err error) {
+func WalkFiles(uri Uri, extensions []string, ignore *Ignore, cb func(Uri, string) error) (err error) {
 	rootPath, err := UriToPath(uri)
 
 	if err != nil {
@@ -59,13 +60,32 @@ func WalkFiles(uri Uri, extensions []string, cb func(Uri, string) error) (err er
 	}
 
 	return filepath.Walk(rootPath, func(path string, info fs.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(rootPath, path)
+		rel = filepath.ToSlash(rel)
+
+		if info.IsDir() {
+			if rel == "." {
+				return nil
+			}
+
+			if ignore.match(rel, true) {
+				return filepath.SkipDir
+			}
+
+			if ignore != nil {
+				_ = ignore.AddFiles(rel, path)
+			}
+
 			return nil
 		}
 
 		ext := Ext(info.Name())
 
-		if !slices.Contains(extensions, ext) {
+		if !slices.Contains(extensions, ext) || ignore.match(rel, false) {
 			return nil
 		}
 
@@ -92,3 +112,19 @@ func createUniqYield[T Family | Member](yield func(*T) bool) func(*T) bool {
 		return !yield(item)
 	}
 }
+
+func toFolderUri(uri Uri) Uri {
+	if !strings.HasSuffix(uri, "/") {
+		uri += "/"
+	}
+
+	return uri
+}
+
+// relativePath returns slash separated path of uri relative to folder
+func relativePath(folder Uri, uri Uri) string {
+	folderPath, _ := UriToPath(toFolderUri(folder))
+	path, _ := UriToPath(uri)
+
+	return strings.TrimPrefix(path, folderPath)
+}